@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 const (
 	HeaderRequestID = "X-Request-ID"
 	HeaderApiVer    = "X-Api-Version"
@@ -7,11 +9,18 @@ const (
 )
 
 type SwaggerItem struct {
-	ID          string `json:"id"`
-	Title       string `json:"title"`
-	Version     string `json:"version"`
-	BasePath    string `json:"base_path"`
-	Description string `json:"description"`
+	ID             string     `json:"id"`
+	Title          string     `json:"title"`
+	Version        string     `json:"version"`
+	BasePath       string     `json:"base_path"`
+	Description    string     `json:"description"`
+	Format         string     `json:"format"`
+	Workspace      string     `json:"workspace"`
+	LastFetched    *time.Time `json:"last_fetched,omitempty"`
+	LastAttempt    *time.Time `json:"last_attempt,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	MissingSince   *time.Time `json:"missing_since,omitempty"`
+	PinnedRevision string     `json:"pinned_revision,omitempty"`
 }
 
 type AsyncapiItem struct {
@@ -19,4 +28,38 @@ type AsyncapiItem struct {
 	Title       string `json:"title"`
 	Version     string `json:"version"`
 	Description string `json:"description"`
+	Format      string `json:"format"`
+}
+
+type DocRevision struct {
+	ID        string      `json:"id"`
+	Timestamp time.Time   `json:"timestamp"`
+	Hash      string      `json:"hash"`
+	Pinned    bool        `json:"pinned,omitempty"`
+	Args      [][2]string `json:"-"`
+}
+
+const (
+	DocChangeAdded   = "added"
+	DocChangeRemoved = "removed"
+	DocChangeChanged = "changed"
+)
+
+type DocDiff struct {
+	From     string      `json:"from"`
+	To       string      `json:"to"`
+	Breaking bool        `json:"breaking"`
+	Changes  []DocChange `json:"changes"`
+}
+
+type DocChange struct {
+	Type        string `json:"type"`
+	Location    string `json:"location"`
+	Description string `json:"description"`
+	Breaking    bool   `json:"breaking"`
+}
+
+type DocChangeSummary struct {
+	Breaking    int `json:"breaking"`
+	NonBreaking int `json:"non_breaking"`
 }
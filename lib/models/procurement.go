@@ -0,0 +1,86 @@
+package models
+
+import "time"
+
+const (
+	ProcurementRunTypeFull     = "full"
+	ProcurementRunTypeFollowUp = "follow-up"
+	ProcurementRunTypeService  = "service"
+)
+
+const (
+	ProcurementOutcomeFetched     = "fetched"
+	ProcurementOutcomeUnchanged   = "unchanged"
+	ProcurementOutcomeUnreachable = "unreachable"
+	ProcurementOutcomeInvalid     = "invalid"
+	ProcurementOutcomeFilteredOut = "filtered-out"
+	ProcurementOutcomeFailed      = "failed"
+)
+
+const (
+	ProcurementReasonDisabled = "disabled"
+	ProcurementReasonDenied   = "denied-by-rules"
+)
+
+const (
+	ProcurementJobStateRunning   = "running"
+	ProcurementJobStateCompleted = "completed"
+	ProcurementJobStateFailed    = "failed"
+	ProcurementJobStateCanceled  = "canceled"
+)
+
+type ProcurementStatus struct {
+	Running bool                 `json:"running"`
+	Runs    []ProcurementRunInfo `json:"runs"`
+}
+
+type ProcurementRunInfo struct {
+	ID         string         `json:"id"`
+	Type       string         `json:"type"`
+	Start      time.Time      `json:"start"`
+	End        time.Time      `json:"end"`
+	Discovered int            `json:"discovered"`
+	Outcomes   map[string]int `json:"outcomes"`
+	Error      string         `json:"error,omitempty"`
+}
+
+type ProcurementRun struct {
+	ProcurementRunInfo
+	Services         []ProcurementServiceResult `json:"services"`
+	SkippedDeletions []string                   `json:"skipped_deletions,omitempty"`
+}
+
+type ProcurementServiceResult struct {
+	ID      string                 `json:"id"`
+	Host    string                 `json:"host"`
+	Port    int                    `json:"port"`
+	DocPath string                 `json:"doc_path,omitempty"`
+	Outcome string                 `json:"outcome"`
+	Reason  string                 `json:"reason,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+	Docs    []ProcurementDocResult `json:"docs,omitempty"`
+}
+
+type ProcurementDocResult struct {
+	ID       string            `json:"id"`
+	BasePath string            `json:"base_path"`
+	Outcome  string            `json:"outcome"`
+	Reason   string            `json:"reason,omitempty"`
+	Error    string            `json:"error,omitempty"`
+	Changes  *DocChangeSummary `json:"changes,omitempty"`
+}
+
+type ProcurementJob struct {
+	ID       string                 `json:"id"`
+	State    string                 `json:"state"`
+	Start    time.Time              `json:"start"`
+	End      *time.Time             `json:"end,omitempty"`
+	Progress ProcurementJobProgress `json:"progress"`
+	RunID    string                 `json:"run_id"`
+	Error    string                 `json:"error,omitempty"`
+}
+
+type ProcurementJobProgress struct {
+	Total int `json:"total"`
+	Done  int `json:"done"`
+}
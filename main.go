@@ -18,13 +18,22 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	lib_models "github.com/SENERGY-Platform/api-docs-provider/lib/models"
 	"github.com/SENERGY-Platform/api-docs-provider/pkg/api"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/components/composite_discovery_hdl"
 	"github.com/SENERGY-Platform/api-docs-provider/pkg/components/discovery_hdl"
 	"github.com/SENERGY-Platform/api-docs-provider/pkg/components/doc_clt"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/components/k8s_clt"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/components/k8s_discovery_hdl"
 	"github.com/SENERGY-Platform/api-docs-provider/pkg/components/kong_clt"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/components/kong_decl_clt"
 	"github.com/SENERGY-Platform/api-docs-provider/pkg/components/ladon_clt"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/components/report_hdl"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/components/static_discovery_hdl"
 	"github.com/SENERGY-Platform/api-docs-provider/pkg/components/storage_hdl"
 	"github.com/SENERGY-Platform/api-docs-provider/pkg/config"
 	"github.com/SENERGY-Platform/api-docs-provider/pkg/service"
@@ -62,19 +71,45 @@ func main() {
 
 	util.InitLogger(cfg.Logger, os.Stderr, "github.com/SENERGY-Platform", srvInfoHdl.Name())
 	discovery_hdl.InitLogger()
+	static_discovery_hdl.InitLogger()
+	k8s_discovery_hdl.InitLogger()
 	swagger_srv.InitLogger()
 	asyncapi_srv.InitLogger()
 
 	util.Logger.Info("starting service", slog_attr.VersionKey, srvInfoHdl.Version(), slog_attr.ConfigValuesKey, sb_config_hdl.StructToMap(cfg, true))
 
-	swaggerStgHdl := storage_hdl.New(cfg.Storage.SwaggerDataPath, "swagger")
-	kongClt := kong_clt.New(&http.Client{Transport: http.DefaultTransport}, cfg.Discovery.Kong.BaseURL, cfg.Discovery.Kong.User, cfg.Discovery.Kong.Password.Value())
-	discoveryHdl := discovery_hdl.New(kongClt, cfg.HttpTimeout, cfg.Discovery.HostBlacklist)
-	docClt := doc_clt.New(&http.Client{Transport: http.DefaultTransport}, cfg.Procurement.SwaggerDocPath)
+	swaggerStgHdl := storage_hdl.New(cfg.Storage.SwaggerDataPath, "swagger", cfg.Storage.Revisions)
+	discoveryHdl, err := newDiscoveryHandler(cfg)
+	if err != nil {
+		util.Logger.Error("creating discovery handler failed", attributes.ErrorKey, err)
+		ec = 1
+		return
+	}
+	docClt := doc_clt.New(&http.Client{Transport: http.DefaultTransport})
 	ladonClt := ladon_clt.New(&http.Client{Transport: http.DefaultTransport}, cfg.Filter.LadonBaseUrl)
-	swaggerSrv := swagger_srv.New(swaggerStgHdl, discoveryHdl, docClt, ladonClt, cfg.HttpTimeout, cfg.ApiGateway, cfg.Filter.AdminRoleName)
+	swaggerDocPaths := cfg.Procurement.SwaggerDocPaths
+	if cfg.Procurement.SwaggerDocPath != "" {
+		swaggerDocPaths = append([]string{cfg.Procurement.SwaggerDocPath}, swaggerDocPaths...)
+	} else if len(swaggerDocPaths) == 0 {
+		swaggerDocPaths = config.DefaultSwaggerDocPaths
+	}
+
+	ctx, cf := context.WithCancel(context.Background())
 
-	asyncapiStgHdl := storage_hdl.New(cfg.Storage.AsyncapiDataPath, "asyncapi")
+	reportHdl := report_hdl.New(cfg.Storage.ReportDataPath, cfg.Procurement.MaxReports)
+	swaggerSrv := swagger_srv.New(ctx, swaggerStgHdl, discoveryHdl, docClt, ladonClt, reportHdl, cfg.HttpTimeout, cfg.ApiGateway, cfg.Filter.AdminRoleName, swagger_srv.ProcurementOptions{
+		DocPaths:           swaggerDocPaths,
+		Concurrency:        cfg.Procurement.Concurrency,
+		HostRateInterval:   cfg.Procurement.HostRateInterval,
+		Retries:            cfg.Procurement.Retries,
+		RetryDelay:         cfg.Procurement.RetryDelay,
+		RetryMaxDelay:      cfg.Procurement.RetryMaxDelay,
+		FollowUpInterval:   cfg.Procurement.FollowUpInterval,
+		MissingGracePeriod: cfg.Procurement.MissingGracePeriod,
+		MaxDeletionPercent: cfg.Procurement.MaxDeletionPercent,
+	})
+
+	asyncapiStgHdl := storage_hdl.New(cfg.Storage.AsyncapiDataPath, "asyncapi", cfg.Storage.Revisions)
 	asyncapiSrv := asyncapi_srv.New(asyncapiStgHdl)
 
 	srv := service.New(swaggerSrv, asyncapiSrv, srvInfoHdl)
@@ -91,8 +126,6 @@ func main() {
 
 	httpServer := util.NewServer(httpHandler, cfg.ServerPort)
 
-	ctx, cf := context.WithCancel(context.Background())
-
 	go func() {
 		util.WaitForSignal(ctx, syscall.SIGINT, syscall.SIGTERM)
 		cf()
@@ -110,6 +143,12 @@ func main() {
 		return
 	}
 
+	if err = reportHdl.Init(ctx); err != nil {
+		util.Logger.Error("initializing report handler failed", attributes.ErrorKey, err)
+		ec = 1
+		return
+	}
+
 	wg := &sync.WaitGroup{}
 
 	wg.Add(1)
@@ -141,3 +180,62 @@ func main() {
 
 	wg.Wait()
 }
+
+func newDiscoveryHandler(cfg *config.Config) (swagger_srv.DiscoveryHandler, error) {
+	rules := append(discovery_hdl.NewHostBlacklistRules(cfg.Discovery.HostBlacklist), cfg.Discovery.Rules...)
+	if err := discovery_hdl.ValidateRules(rules); err != nil {
+		return nil, err
+	}
+	var handlers []composite_discovery_hdl.DiscoveryHandler
+	for _, backend := range cfg.Discovery.Backends {
+		if (backend == config.DiscoveryBackendStatic || backend == config.DiscoveryBackendKubernetes) && discovery_hdl.HasKongOnlyRules(rules) {
+			util.Logger.Warn("discovery rules with service or route fields do not match services of discovery backend", slog_attr.ComponentKey, backend)
+		}
+		switch backend {
+		case config.DiscoveryBackendKong:
+			kongClt := kong_clt.New(&http.Client{Transport: http.DefaultTransport}, cfg.Discovery.Kong.BaseURL, cfg.Discovery.Kong.User, cfg.Discovery.Kong.Password.Value(), cfg.Discovery.Kong.PageSize, cfg.Discovery.Kong.Workspaces)
+			handlers = append(handlers, discovery_hdl.New(kongClt, cfg.HttpTimeout, rules))
+		case config.DiscoveryBackendKongDeclarative:
+			handlers = append(handlers, discovery_hdl.New(kong_decl_clt.New(cfg.Discovery.Kong.DeclarativeConfigPath), cfg.HttpTimeout, rules))
+		case config.DiscoveryBackendStatic:
+			handlers = append(handlers, static_discovery_hdl.New(cfg.Discovery.Static.Path, rules))
+		case config.DiscoveryBackendKubernetes:
+			transport, err := newK8sTransport(cfg.Discovery.Kubernetes.CAPath)
+			if err != nil {
+				return nil, err
+			}
+			k8sClt := k8s_clt.New(&http.Client{Transport: transport}, cfg.Discovery.Kubernetes.BaseURL, cfg.Discovery.Kubernetes.TokenPath, cfg.Discovery.Kubernetes.PageSize)
+			handlers = append(handlers, k8s_discovery_hdl.New(k8sClt, cfg.Discovery.Kubernetes.Namespaces, cfg.HttpTimeout, rules))
+		default:
+			return nil, fmt.Errorf("unknown discovery backend '%s'", backend)
+		}
+	}
+	switch len(handlers) {
+	case 0:
+		return nil, errors.New("no discovery backends")
+	case 1:
+		return handlers[0], nil
+	default:
+		return composite_discovery_hdl.New(handlers...), nil
+	}
+}
+
+func newK8sTransport(caPath string) (http.RoundTripper, error) {
+	if caPath == "" {
+		return http.DefaultTransport, nil
+	}
+	b, err := os.ReadFile(caPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return http.DefaultTransport, nil
+		}
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("invalid ca file '%s'", caPath)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	return transport, nil
+}
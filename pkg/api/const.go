@@ -21,6 +21,15 @@ const (
 	HeaderAuthorization = "Authorization"
 )
 
+const (
+	QueryAsync = "async"
+	QueryForce = "force"
+	QueryFrom  = "from"
+	QueryTo    = "to"
+)
+
 const (
 	HealthCheckPath = "/health-check"
 )
+
+const MIMEYAML = "application/yaml"
@@ -17,6 +17,7 @@
 package api
 
 import (
+	"context"
 	"errors"
 	lib_models "github.com/SENERGY-Platform/api-docs-provider/lib/models"
 	"net/http"
@@ -43,5 +44,8 @@ func GetStatusCode(err error) int {
 	if errors.As(err, &rbe) {
 		return http.StatusConflict
 	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusServiceUnavailable
+	}
 	return 0
 }
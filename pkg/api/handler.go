@@ -29,6 +29,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -87,7 +88,7 @@ func getSwaggerGetDocsH(srv Service) (string, string, gin.HandlerFunc) {
 // @Summary Get doc
 // @Description Get a swagger doc.
 // @Tags Swagger
-// @Produce	json
+// @Produce	json,yaml
 // @Param Authorization header string false "jwt token"
 // @Param X-User-Roles header string false "user roles"
 // @Param id path string true "doc id"
@@ -107,20 +108,238 @@ func getSwaggerGetDocH(srv Service) (string, string, gin.HandlerFunc) {
 			_ = gc.Error(err)
 			return
 		}
-		gc.Data(http.StatusOK, gin.MIMEJSON, doc)
+		writeDoc(gc, doc)
+	}
+}
+
+// getSwaggerListRevisionsH godoc
+// @Summary List doc revisions
+// @Description List stored revisions of a swagger doc, newest first.
+// @Tags Swagger
+// @Produce	json
+// @Param Authorization header string false "jwt token"
+// @Param X-User-Roles header string false "user roles"
+// @Param id path string true "doc id"
+// @Success	200 {array} models.DocRevision "doc revisions"
+// @Failure	403 {string} string "error message"
+// @Failure	404 {string} string "error message"
+// @Failure	500 {string} string "error message"
+// @Router /docs/swagger/{id}/revisions [get]
+func getSwaggerListRevisionsH(srv Service) (string, string, gin.HandlerFunc) {
+	return http.MethodGet, "/docs/swagger/:id/revisions", func(gc *gin.Context) {
+		var userRoles []string
+		if val := gc.GetHeader(HeaderUserRoles); val != "" {
+			userRoles = strings.Split(val, ", ")
+		}
+		revisions, err := srv.SwaggerListRevisions(context.WithValue(gc.Request.Context(), models.ContextRequestID, requestid.Get(gc)), gc.Param("id"), gc.Request.Header.Get(HeaderAuthorization), userRoles)
+		if err != nil {
+			_ = gc.Error(err)
+			return
+		}
+		gc.JSON(http.StatusOK, revisions)
+	}
+}
+
+// getSwaggerGetRevisionH godoc
+// @Summary Get doc revision
+// @Description Get a specific revision of a swagger doc.
+// @Tags Swagger
+// @Produce	json,yaml
+// @Param Authorization header string false "jwt token"
+// @Param X-User-Roles header string false "user roles"
+// @Param id path string true "doc id"
+// @Param revision path string true "revision id"
+// @Success	200 {object} object "swagger doc"
+// @Failure	403 {string} string "error message"
+// @Failure	404 {string} string "error message"
+// @Failure	500 {string} string "error message"
+// @Router /docs/swagger/{id}/revisions/{revision} [get]
+func getSwaggerGetRevisionH(srv Service) (string, string, gin.HandlerFunc) {
+	return http.MethodGet, "/docs/swagger/:id/revisions/:revision", func(gc *gin.Context) {
+		var userRoles []string
+		if val := gc.GetHeader(HeaderUserRoles); val != "" {
+			userRoles = strings.Split(val, ", ")
+		}
+		doc, err := srv.SwaggerGetRevision(context.WithValue(gc.Request.Context(), models.ContextRequestID, requestid.Get(gc)), gc.Param("id"), gc.Param("revision"), gc.Request.Header.Get(HeaderAuthorization), userRoles)
+		if err != nil {
+			_ = gc.Error(err)
+			return
+		}
+		writeDoc(gc, doc)
+	}
+}
+
+// getSwaggerDiffH godoc
+// @Summary Diff doc revisions
+// @Description Compare two revisions of a swagger doc and classify changes as breaking or non-breaking. Defaults to the previous and current revision.
+// @Tags Swagger
+// @Produce	json
+// @Param Authorization header string false "jwt token"
+// @Param X-User-Roles header string false "user roles"
+// @Param id path string true "doc id"
+// @Param from query string false "revision id to compare from"
+// @Param to query string false "revision id to compare to"
+// @Success	200 {object} models.DocDiff "doc diff"
+// @Failure	400 {string} string "error message"
+// @Failure	403 {string} string "error message"
+// @Failure	404 {string} string "error message"
+// @Failure	500 {string} string "error message"
+// @Router /docs/swagger/{id}/diff [get]
+func getSwaggerDiffH(srv Service) (string, string, gin.HandlerFunc) {
+	return http.MethodGet, "/docs/swagger/:id/diff", func(gc *gin.Context) {
+		var userRoles []string
+		if val := gc.GetHeader(HeaderUserRoles); val != "" {
+			userRoles = strings.Split(val, ", ")
+		}
+		diff, err := srv.SwaggerDiff(context.WithValue(gc.Request.Context(), models.ContextRequestID, requestid.Get(gc)), gc.Param("id"), gc.Query(QueryFrom), gc.Query(QueryTo), gc.Request.Header.Get(HeaderAuthorization), userRoles)
+		if err != nil {
+			_ = gc.Error(err)
+			return
+		}
+		gc.JSON(http.StatusOK, diff)
 	}
 }
 
 // patchSwaggerRefreshDocsH godoc
 // @Summary Refresh storage
-// @Description Trigger swagger docs refresh.
+// @Description Trigger swagger docs refresh. If async is set, the refresh runs as a job and the job is returned immediately. If force is set, old docs are removed even if the deletion limit is exceeded.
 // @Tags Swagger
+// @Produce	json
+// @Param async query bool false "run refresh as job"
+// @Param force query bool false "ignore deletion limit"
 // @Success	200
+// @Success	202 {object} models.ProcurementJob "refresh job"
+// @Failure	400 {string} string "error message"
+// @Failure	409 {string} string "error message"
 // @Failure	500 {string} string "error message"
+// @Failure	503 {string} string "error message"
 // @Router /storage-refresh/swagger [patch]
 func patchSwaggerRefreshDocsH(srv Service) (string, string, gin.HandlerFunc) {
 	return http.MethodPatch, "/storage-refresh/swagger", func(gc *gin.Context) {
-		err := srv.SwaggerRefreshDocs(context.WithValue(gc.Request.Context(), models.ContextRequestID, requestid.Get(gc)))
+		async, err := getBoolQuery(gc, QueryAsync)
+		if err != nil {
+			_ = gc.Error(err)
+			return
+		}
+		force, err := getBoolQuery(gc, QueryForce)
+		if err != nil {
+			_ = gc.Error(err)
+			return
+		}
+		ctx := context.WithValue(gc.Request.Context(), models.ContextRequestID, requestid.Get(gc))
+		if async {
+			job, err := srv.SwaggerStartRefreshDocs(ctx, force)
+			if err != nil {
+				_ = gc.Error(err)
+				return
+			}
+			gc.JSON(http.StatusAccepted, job)
+			return
+		}
+		err = srv.SwaggerRefreshDocs(ctx, force)
+		if err != nil {
+			_ = gc.Error(err)
+			return
+		}
+		gc.Status(http.StatusOK)
+	}
+}
+
+// patchSwaggerRefreshServiceH godoc
+// @Summary Refresh service
+// @Description Trigger docs refresh for a single service. The service can be referenced by service id, host or storage id.
+// @Tags Swagger
+// @Produce	json
+// @Param id path string true "service id, host or storage id"
+// @Success	200 {object} models.ProcurementRun "procurement run"
+// @Failure	404 {string} string "error message"
+// @Failure	500 {string} string "error message"
+// @Router /storage-refresh/swagger/{id} [patch]
+func patchSwaggerRefreshServiceH(srv Service) (string, string, gin.HandlerFunc) {
+	return http.MethodPatch, "/storage-refresh/swagger/:id", func(gc *gin.Context) {
+		run, err := srv.SwaggerRefreshService(context.WithValue(gc.Request.Context(), models.ContextRequestID, requestid.Get(gc)), gc.Param("id"))
+		if err != nil {
+			_ = gc.Error(err)
+			return
+		}
+		gc.JSON(http.StatusOK, run)
+	}
+}
+
+// getProcurementStatusH godoc
+// @Summary Get procurement status
+// @Description Get procurement state and reports of recent runs.
+// @Tags Procurement
+// @Produce	json
+// @Success	200 {object} models.ProcurementStatus "procurement status"
+// @Failure	500 {string} string "error message"
+// @Router /procurement/status [get]
+func getProcurementStatusH(srv Service) (string, string, gin.HandlerFunc) {
+	return http.MethodGet, "/procurement/status", func(gc *gin.Context) {
+		status, err := srv.SwaggerGetProcurementStatus(context.WithValue(gc.Request.Context(), models.ContextRequestID, requestid.Get(gc)))
+		if err != nil {
+			_ = gc.Error(err)
+			return
+		}
+		gc.JSON(http.StatusOK, status)
+	}
+}
+
+// getProcurementRunH godoc
+// @Summary Get procurement run
+// @Description Get the report of a procurement run.
+// @Tags Procurement
+// @Produce	json
+// @Param id path string true "run id"
+// @Success	200 {object} models.ProcurementRun "procurement run"
+// @Failure	404 {string} string "error message"
+// @Failure	500 {string} string "error message"
+// @Router /procurement/runs/{id} [get]
+func getProcurementRunH(srv Service) (string, string, gin.HandlerFunc) {
+	return http.MethodGet, "/procurement/runs/:id", func(gc *gin.Context) {
+		run, err := srv.SwaggerGetProcurementRun(context.WithValue(gc.Request.Context(), models.ContextRequestID, requestid.Get(gc)), gc.Param("id"))
+		if err != nil {
+			_ = gc.Error(err)
+			return
+		}
+		gc.JSON(http.StatusOK, run)
+	}
+}
+
+// getRefreshJobH godoc
+// @Summary Get refresh job
+// @Description Get state and progress of a refresh job.
+// @Tags Procurement
+// @Produce	json
+// @Param id path string true "job id"
+// @Success	200 {object} models.ProcurementJob "refresh job"
+// @Failure	404 {string} string "error message"
+// @Failure	500 {string} string "error message"
+// @Router /procurement/jobs/{id} [get]
+func getRefreshJobH(srv Service) (string, string, gin.HandlerFunc) {
+	return http.MethodGet, "/procurement/jobs/:id", func(gc *gin.Context) {
+		job, err := srv.SwaggerGetRefreshJob(context.WithValue(gc.Request.Context(), models.ContextRequestID, requestid.Get(gc)), gc.Param("id"))
+		if err != nil {
+			_ = gc.Error(err)
+			return
+		}
+		gc.JSON(http.StatusOK, job)
+	}
+}
+
+// deleteRefreshJobH godoc
+// @Summary Cancel refresh job
+// @Description Cancel a running refresh job.
+// @Tags Procurement
+// @Success	200
+// @Param id path string true "job id"
+// @Failure	400 {string} string "error message"
+// @Failure	404 {string} string "error message"
+// @Failure	500 {string} string "error message"
+// @Router /procurement/jobs/{id} [delete]
+func deleteRefreshJobH(srv Service) (string, string, gin.HandlerFunc) {
+	return http.MethodDelete, "/procurement/jobs/:id", func(gc *gin.Context) {
+		err := srv.SwaggerCancelRefreshJob(context.WithValue(gc.Request.Context(), models.ContextRequestID, requestid.Get(gc)), gc.Param("id"))
 		if err != nil {
 			_ = gc.Error(err)
 			return
@@ -152,6 +371,87 @@ func getSwaggerListStorageH(srv Service) (string, string, gin.HandlerFunc) {
 	}
 }
 
+// putSwaggerPinRevisionH godoc
+// @Summary Pin doc revision
+// @Description Pin a stored swagger doc to a revision. Procurement keeps storing new revisions, but the pinned revision is served until the doc is unpinned.
+// @Tags Swagger
+// @Param X-User-Roles header string false "user roles"
+// @Param id path string true "doc id"
+// @Param revision path string true "revision id"
+// @Success	200
+// @Failure	403 {string} string "error message"
+// @Failure	404 {string} string "error message"
+// @Failure	500 {string} string "error message"
+// @Router /storage/swagger/{id}/pin/{revision} [put]
+func putSwaggerPinRevisionH(srv Service) (string, string, gin.HandlerFunc) {
+	return http.MethodPut, "/storage/swagger/:id/pin/:revision", func(gc *gin.Context) {
+		var userRoles []string
+		if val := gc.GetHeader(HeaderUserRoles); val != "" {
+			userRoles = strings.Split(val, ", ")
+		}
+		err := srv.SwaggerPinRevision(context.WithValue(gc.Request.Context(), models.ContextRequestID, requestid.Get(gc)), gc.Param("id"), gc.Param("revision"), userRoles)
+		if err != nil {
+			_ = gc.Error(err)
+			return
+		}
+		gc.Status(http.StatusOK)
+	}
+}
+
+// deleteSwaggerPinRevisionH godoc
+// @Summary Unpin doc
+// @Description Remove the pin of a stored swagger doc, the latest revision is served again.
+// @Tags Swagger
+// @Param X-User-Roles header string false "user roles"
+// @Param id path string true "doc id"
+// @Success	200
+// @Failure	403 {string} string "error message"
+// @Failure	404 {string} string "error message"
+// @Failure	500 {string} string "error message"
+// @Router /storage/swagger/{id}/pin [delete]
+func deleteSwaggerPinRevisionH(srv Service) (string, string, gin.HandlerFunc) {
+	return http.MethodDelete, "/storage/swagger/:id/pin", func(gc *gin.Context) {
+		var userRoles []string
+		if val := gc.GetHeader(HeaderUserRoles); val != "" {
+			userRoles = strings.Split(val, ", ")
+		}
+		err := srv.SwaggerUnpinRevision(context.WithValue(gc.Request.Context(), models.ContextRequestID, requestid.Get(gc)), gc.Param("id"), userRoles)
+		if err != nil {
+			_ = gc.Error(err)
+			return
+		}
+		gc.Status(http.StatusOK)
+	}
+}
+
+// patchSwaggerRollbackH godoc
+// @Summary Rollback doc
+// @Description Store a previous revision of a swagger doc as the latest revision and remove an existing pin. The next procurement may replace the doc again, pin the revision to keep it.
+// @Tags Swagger
+// @Param X-User-Roles header string false "user roles"
+// @Param id path string true "doc id"
+// @Param revision path string true "revision id"
+// @Success	200
+// @Failure	400 {string} string "error message"
+// @Failure	403 {string} string "error message"
+// @Failure	404 {string} string "error message"
+// @Failure	500 {string} string "error message"
+// @Router /storage/swagger/{id}/rollback/{revision} [patch]
+func patchSwaggerRollbackH(srv Service) (string, string, gin.HandlerFunc) {
+	return http.MethodPatch, "/storage/swagger/:id/rollback/:revision", func(gc *gin.Context) {
+		var userRoles []string
+		if val := gc.GetHeader(HeaderUserRoles); val != "" {
+			userRoles = strings.Split(val, ", ")
+		}
+		err := srv.SwaggerRollback(context.WithValue(gc.Request.Context(), models.ContextRequestID, requestid.Get(gc)), gc.Param("id"), gc.Param("revision"), userRoles)
+		if err != nil {
+			_ = gc.Error(err)
+			return
+		}
+		gc.Status(http.StatusOK)
+	}
+}
+
 // getAsyncapiGetDocsH godoc
 // @Summary Get docs
 // @Description Get all asyncapi docs.
@@ -176,7 +476,7 @@ func getAsyncapiGetDocsH(srv Service) (string, string, gin.HandlerFunc) {
 // @Summary Get doc
 // @Description Get an asyncapi doc.
 // @Tags AsyncAPI
-// @Produce	json
+// @Produce	json,yaml
 // @Param Authorization header string false "jwt token"
 // @Param id path string true "doc id"
 // @Success	200 {object} object "asyncapi doc"
@@ -190,7 +490,74 @@ func getAsyncapiGetDocH(srv Service) (string, string, gin.HandlerFunc) {
 			_ = gc.Error(err)
 			return
 		}
-		gc.Data(http.StatusOK, gin.MIMEJSON, doc)
+		writeDoc(gc, doc)
+	}
+}
+
+// getAsyncapiListRevisionsH godoc
+// @Summary List doc revisions
+// @Description List stored revisions of an asyncapi doc, newest first.
+// @Tags AsyncAPI
+// @Produce	json
+// @Param id path string true "doc id"
+// @Success	200 {array} models.DocRevision "doc revisions"
+// @Failure	404 {string} string "error message"
+// @Failure	500 {string} string "error message"
+// @Router /docs/asyncapi/{id}/revisions [get]
+func getAsyncapiListRevisionsH(srv Service) (string, string, gin.HandlerFunc) {
+	return http.MethodGet, "/docs/asyncapi/:id/revisions", func(gc *gin.Context) {
+		revisions, err := srv.AsyncapiListRevisions(context.WithValue(gc.Request.Context(), models.ContextRequestID, requestid.Get(gc)), gc.Param("id"))
+		if err != nil {
+			_ = gc.Error(err)
+			return
+		}
+		gc.JSON(http.StatusOK, revisions)
+	}
+}
+
+// getAsyncapiGetRevisionH godoc
+// @Summary Get doc revision
+// @Description Get a specific revision of an asyncapi doc.
+// @Tags AsyncAPI
+// @Produce	json,yaml
+// @Param id path string true "doc id"
+// @Param revision path string true "revision id"
+// @Success	200 {object} object "asyncapi doc"
+// @Failure	404 {string} string "error message"
+// @Failure	500 {string} string "error message"
+// @Router /docs/asyncapi/{id}/revisions/{revision} [get]
+func getAsyncapiGetRevisionH(srv Service) (string, string, gin.HandlerFunc) {
+	return http.MethodGet, "/docs/asyncapi/:id/revisions/:revision", func(gc *gin.Context) {
+		doc, err := srv.AsyncapiGetRevision(context.WithValue(gc.Request.Context(), models.ContextRequestID, requestid.Get(gc)), gc.Param("id"), gc.Param("revision"))
+		if err != nil {
+			_ = gc.Error(err)
+			return
+		}
+		writeDoc(gc, doc)
+	}
+}
+
+// getAsyncapiDiffH godoc
+// @Summary Diff doc revisions
+// @Description Compare two revisions of an asyncapi doc and classify changes as breaking or non-breaking. Defaults to the previous and current revision.
+// @Tags AsyncAPI
+// @Produce	json
+// @Param id path string true "doc id"
+// @Param from query string false "revision id to compare from"
+// @Param to query string false "revision id to compare to"
+// @Success	200 {object} models.DocDiff "doc diff"
+// @Failure	400 {string} string "error message"
+// @Failure	404 {string} string "error message"
+// @Failure	500 {string} string "error message"
+// @Router /docs/asyncapi/{id}/diff [get]
+func getAsyncapiDiffH(srv Service) (string, string, gin.HandlerFunc) {
+	return http.MethodGet, "/docs/asyncapi/:id/diff", func(gc *gin.Context) {
+		diff, err := srv.AsyncapiDiff(context.WithValue(gc.Request.Context(), models.ContextRequestID, requestid.Get(gc)), gc.Param("id"), gc.Query(QueryFrom), gc.Query(QueryTo))
+		if err != nil {
+			_ = gc.Error(err)
+			return
+		}
+		gc.JSON(http.StatusOK, diff)
 	}
 }
 
@@ -216,9 +583,9 @@ func getAsyncapiListStorage(srv Service) (string, string, gin.HandlerFunc) {
 
 // putAsyncapiPutDocH godoc
 // @Summary Store doc
-// @Description Store an asyncapi doc.
+// @Description Store an asyncapi doc. The doc format is taken from the content type or detected from the data.
 // @Tags AsyncAPI
-// @Accept octet-stream
+// @Accept json,yaml,octet-stream
 // @Param Authorization header string false "jwt token"
 // @Param id path string true "doc id"
 // @Param data body string true "doc"
@@ -238,7 +605,7 @@ func putAsyncapiPutDocH(srv Service) (string, string, gin.HandlerFunc) {
 			_ = gc.Error(err)
 			return
 		}
-		err = srv.AsyncapiPutDoc(context.WithValue(gc.Request.Context(), models.ContextRequestID, requestid.Get(gc)), id, data)
+		err = srv.AsyncapiPutDoc(context.WithValue(gc.Request.Context(), models.ContextRequestID, requestid.Get(gc)), id, gc.ContentType(), data)
 		if err != nil {
 			_ = gc.Error(err)
 			return
@@ -300,3 +667,29 @@ func getSwaggerDocH(_ Service) (string, string, gin.HandlerFunc) {
 		gc.File("docs/swagger.json")
 	}
 }
+
+func writeDoc(gc *gin.Context, doc []byte) {
+	switch gc.NegotiateFormat(gin.MIMEJSON, MIMEYAML, gin.MIMEYAML) {
+	case MIMEYAML, gin.MIMEYAML:
+		b, err := util.JSONToYAML(doc)
+		if err != nil {
+			_ = gc.Error(lib_models.NewInternalError(err))
+			return
+		}
+		gc.Data(http.StatusOK, MIMEYAML, b)
+	default:
+		gc.Data(http.StatusOK, gin.MIMEJSON, doc)
+	}
+}
+
+func getBoolQuery(gc *gin.Context, key string) (bool, error) {
+	val := gc.Query(key)
+	if val == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, lib_models.NewInvalidInputError(err)
+	}
+	return b, nil
+}
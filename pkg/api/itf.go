@@ -27,11 +27,26 @@ type Service interface {
 	SwaggerGetDocs(ctx context.Context, userToken string, userRoles []string) ([]map[string]json.RawMessage, error)
 	SwaggerGetDoc(ctx context.Context, id, userToken string, userRoles []string) ([]byte, error)
 	SwaggerListStorage(ctx context.Context, userToken string, userRoles []string) ([]lib_models.SwaggerItem, error)
-	SwaggerRefreshDocs(ctx context.Context) error
+	SwaggerListRevisions(ctx context.Context, id string, userToken string, userRoles []string) ([]lib_models.DocRevision, error)
+	SwaggerGetRevision(ctx context.Context, id, revisionID string, userToken string, userRoles []string) ([]byte, error)
+	SwaggerDiff(ctx context.Context, id, from, to string, userToken string, userRoles []string) (lib_models.DocDiff, error)
+	SwaggerPinRevision(ctx context.Context, id, revisionID string, userRoles []string) error
+	SwaggerUnpinRevision(ctx context.Context, id string, userRoles []string) error
+	SwaggerRollback(ctx context.Context, id, revisionID string, userRoles []string) error
+	SwaggerRefreshDocs(ctx context.Context, force bool) error
+	SwaggerRefreshService(ctx context.Context, ref string) (lib_models.ProcurementRun, error)
+	SwaggerStartRefreshDocs(ctx context.Context, force bool) (lib_models.ProcurementJob, error)
+	SwaggerGetRefreshJob(ctx context.Context, id string) (lib_models.ProcurementJob, error)
+	SwaggerCancelRefreshJob(ctx context.Context, id string) error
+	SwaggerGetProcurementStatus(ctx context.Context) (lib_models.ProcurementStatus, error)
+	SwaggerGetProcurementRun(ctx context.Context, id string) (lib_models.ProcurementRun, error)
 	AsyncapiGetDocs(ctx context.Context) ([]json.RawMessage, error)
 	AsyncapiGetDoc(ctx context.Context, id string) ([]byte, error)
-	AsyncapiPutDoc(ctx context.Context, id string, data []byte) error
+	AsyncapiPutDoc(ctx context.Context, id, contentType string, data []byte) error
 	AsyncapiDeleteDoc(ctx context.Context, id string) error
 	AsyncapiListStorage(ctx context.Context) ([]lib_models.AsyncapiItem, error)
+	AsyncapiListRevisions(ctx context.Context, id string) ([]lib_models.DocRevision, error)
+	AsyncapiGetRevision(ctx context.Context, id, revisionID string) ([]byte, error)
+	AsyncapiDiff(ctx context.Context, id, from, to string) (lib_models.DocDiff, error)
 	ServiceInfo() srv_info_hdl.ServiceInfo
 }
@@ -24,10 +24,24 @@ var routes = gin_mw.Routes[Service]{
 	getSwaggerGetDocsOldH,
 	getSwaggerGetDocsH,
 	getSwaggerGetDocH,
+	getSwaggerListRevisionsH,
+	getSwaggerGetRevisionH,
+	getSwaggerDiffH,
 	patchSwaggerRefreshDocsH,
+	patchSwaggerRefreshServiceH,
 	getSwaggerListStorageH,
+	putSwaggerPinRevisionH,
+	deleteSwaggerPinRevisionH,
+	patchSwaggerRollbackH,
+	getProcurementStatusH,
+	getProcurementRunH,
+	getRefreshJobH,
+	deleteRefreshJobH,
 	getAsyncapiGetDocsH,
 	getAsyncapiGetDocH,
+	getAsyncapiListRevisionsH,
+	getAsyncapiGetRevisionH,
+	getAsyncapiDiffH,
 	getAsyncapiListStorage,
 	putAsyncapiPutDocH,
 	deleteAsyncapiDeleteDocH,
@@ -0,0 +1,89 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package composite_discovery_hdl
+
+import (
+	"context"
+	"fmt"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util"
+	"maps"
+	"slices"
+)
+
+type DiscoveryHandler interface {
+	GetServices(ctx context.Context) (map[string]models.Service, error)
+}
+
+type Handler struct {
+	handlers []DiscoveryHandler
+}
+
+func New(handlers ...DiscoveryHandler) *Handler {
+	return &Handler{handlers: handlers}
+}
+
+func (h *Handler) GetServices(ctx context.Context) (map[string]models.Service, error) {
+	services := make(map[string]models.Service)
+	ids := make(map[string]string)
+	for _, handler := range h.handlers {
+		items, err := handler.GetServices(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			key := fmt.Sprintf("%s:%s:%d", item.Workspace, item.Host, item.Port)
+			id, ok := ids[key]
+			if !ok {
+				item.ExtPaths = slices.Clone(item.ExtPaths)
+				item.DeniedPaths = slices.Clone(item.DeniedPaths)
+				item.Routes = maps.Clone(item.Routes)
+				services[item.ID] = item
+				ids[key] = item.ID
+				continue
+			}
+			services[id] = mergeServices(services[id], item)
+		}
+	}
+	return services, nil
+}
+
+func mergeServices(a, b models.Service) models.Service {
+	for _, extPath := range b.ExtPaths {
+		if slices.Contains(a.ExtPaths, extPath) {
+			continue
+		}
+		a.ExtPaths = append(a.ExtPaths, extPath)
+		if route, ok := b.Routes[extPath]; ok {
+			if a.Routes == nil {
+				a.Routes = make(map[string]models.Route)
+			}
+			a.Routes[extPath] = route
+		}
+	}
+	for _, extPath := range b.DeniedPaths {
+		if !slices.Contains(a.DeniedPaths, extPath) {
+			a.DeniedPaths = append(a.DeniedPaths, extPath)
+		}
+	}
+	a.DeniedPaths = slices.DeleteFunc(a.DeniedPaths, func(extPath string) bool {
+		return slices.Contains(a.ExtPaths, extPath)
+	})
+	a.Denied = a.Denied && b.Denied
+	a.DocOptions = util.MergeDocOptions(a.DocOptions, b.DocOptions)
+	return a
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package composite_discovery_hdl
+
+import (
+	"context"
+	"errors"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHandler_GetServices(t *testing.T) {
+	hdlA := &mockHandler{
+		Services: map[string]models.Service{
+			"a80": {
+				ID:       "a80",
+				Host:     "a",
+				Port:     80,
+				Protocol: "http",
+				ExtPaths: []string{"/a"},
+				Routes: map[string]models.Route{
+					"/a": {StripPath: true},
+				},
+				DocOptions: models.DocOptions{Path: "/doc"},
+			},
+		},
+	}
+	hdlB := &mockHandler{
+		Services: map[string]models.Service{
+			"a:80": {
+				ID:       "a:80",
+				Host:     "a",
+				Port:     80,
+				Protocol: "https",
+				ExtPaths: []string{"/a", "/b"},
+				Routes: map[string]models.Route{
+					"/a": {},
+					"/b": {Methods: []string{"GET"}},
+				},
+				DocOptions: models.DocOptions{Path: "/openapi.json", Timeout: time.Second},
+			},
+			"b:80": {
+				ID:       "b:80",
+				Host:     "b",
+				Port:     80,
+				Protocol: "http",
+				ExtPaths: []string{"/c"},
+			},
+		},
+	}
+	hdl := New(hdlA, hdlB)
+	a := map[string]models.Service{
+		"a80": {
+			ID:       "a80",
+			Host:     "a",
+			Port:     80,
+			Protocol: "http",
+			ExtPaths: []string{"/a", "/b"},
+			Routes: map[string]models.Route{
+				"/a": {StripPath: true},
+				"/b": {Methods: []string{"GET"}},
+			},
+			DocOptions: models.DocOptions{Path: "/doc", Timeout: time.Second},
+		},
+		"b:80": {
+			ID:       "b:80",
+			Host:     "b",
+			Port:     80,
+			Protocol: "http",
+			ExtPaths: []string{"/c"},
+		},
+	}
+	b, err := hdl.GetServices(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("expected: %v, got: %v", a, b)
+	}
+	if len(hdlA.Services["a80"].ExtPaths) != 1 || len(hdlA.Services["a80"].Routes) != 1 {
+		t.Error("backend services modified")
+	}
+	t.Run("path without route", func(t *testing.T) {
+		hdl := New(
+			&mockHandler{Services: map[string]models.Service{"c80": {ID: "c80", Host: "c", Port: 80, ExtPaths: []string{"/a"}}}},
+			&mockHandler{Services: map[string]models.Service{"c:80": {ID: "c:80", Host: "c", Port: 80, ExtPaths: []string{"/b"}}}},
+		)
+		b, err := hdl.GetServices(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		a := map[string]models.Service{"c80": {ID: "c80", Host: "c", Port: 80, ExtPaths: []string{"/a", "/b"}}}
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("expected: %v, got: %v", a, b)
+		}
+	})
+	t.Run("denied paths", func(t *testing.T) {
+		hdl := New(
+			&mockHandler{Services: map[string]models.Service{"d80": {ID: "d80", Host: "d", Port: 80, Denied: true, DeniedPaths: []string{"/a", "/b"}}}},
+			&mockHandler{Services: map[string]models.Service{"d:80": {ID: "d:80", Host: "d", Port: 80, ExtPaths: []string{"/a"}}}},
+		)
+		b, err := hdl.GetServices(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		a := map[string]models.Service{"d80": {ID: "d80", Host: "d", Port: 80, ExtPaths: []string{"/a"}, DeniedPaths: []string{"/b"}}}
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("expected: %v, got: %v", a, b)
+		}
+	})
+	t.Run("workspaces", func(t *testing.T) {
+		hdl := New(
+			&mockHandler{Services: map[string]models.Service{"ws-a_c80": {ID: "ws-a_c80", Host: "c", Port: 80, Workspace: "ws-a", ExtPaths: []string{"/a"}}}},
+			&mockHandler{Services: map[string]models.Service{"ws-b_c80": {ID: "ws-b_c80", Host: "c", Port: 80, Workspace: "ws-b", ExtPaths: []string{"/b"}}}},
+		)
+		b, err := hdl.GetServices(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) != 2 {
+			t.Errorf("expected 2 services, got %v", b)
+		}
+	})
+	t.Run("error", func(t *testing.T) {
+		hdlB.Err = errors.New("error")
+		if _, err = hdl.GetServices(context.Background()); err == nil {
+			t.Error("expected error")
+		}
+		hdlB.Err = nil
+	})
+}
+
+type mockHandler struct {
+	Services map[string]models.Service
+	Err      error
+}
+
+func (m *mockHandler) GetServices(_ context.Context) (map[string]models.Service, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Services, nil
+}
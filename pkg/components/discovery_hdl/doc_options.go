@@ -0,0 +1,42 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package discovery_hdl
+
+import (
+	"errors"
+	"fmt"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util"
+	"strings"
+)
+
+const docTagPrefix = "api-docs:"
+
+func parseDocOptions(tags []string) (models.DocOptions, error) {
+	var opts models.DocOptions
+	var errs []error
+	for _, tag := range tags {
+		option, ok := strings.CutPrefix(tag, docTagPrefix)
+		if !ok {
+			continue
+		}
+		if err := util.ParseDocOption(&opts, option); err != nil {
+			errs = append(errs, fmt.Errorf("tag '%s': %w", tag, err))
+		}
+	}
+	return opts, errors.Join(errs...)
+}
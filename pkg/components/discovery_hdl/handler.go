@@ -22,25 +22,27 @@ import (
 	lib_models "github.com/SENERGY-Platform/api-docs-provider/lib/models"
 	"github.com/SENERGY-Platform/api-docs-provider/pkg/components/kong_clt"
 	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util"
 	"github.com/SENERGY-Platform/api-docs-provider/pkg/util/slog_attr"
+	"github.com/SENERGY-Platform/go-service-base/struct-logger/attributes"
+	"slices"
+	"strings"
 	"time"
 )
 
+const defaultWorkspace = "default"
+
 type Handler struct {
-	kongClient    kong_clt.ClientItf
-	timeout       time.Duration
-	hostBlacklist map[string]struct{}
+	kongClient kong_clt.ClientItf
+	timeout    time.Duration
+	rules      []models.DiscoveryRule
 }
 
-func New(kongClient kong_clt.ClientItf, timeout time.Duration, hostBlacklist []string) *Handler {
-	blackList := make(map[string]struct{})
-	for _, host := range hostBlacklist {
-		blackList[host] = struct{}{}
-	}
+func New(kongClient kong_clt.ClientItf, timeout time.Duration, rules []models.DiscoveryRule) *Handler {
 	return &Handler{
-		kongClient:    kongClient,
-		timeout:       timeout,
-		hostBlacklist: blackList,
+		kongClient: kongClient,
+		timeout:    timeout,
+		rules:      rules,
 	}
 }
 
@@ -59,6 +61,7 @@ func (h *Handler) GetServices(ctx context.Context) (map[string]models.Service, e
 	}
 	kSrvMap := getKongSrvMap(kServices)
 	services := make(map[string]models.Service)
+	optsSet := make(map[string]struct{})
 	for _, kRoute := range kRoutes {
 		if len(kRoute.Paths) == 0 {
 			continue
@@ -67,26 +70,83 @@ func (h *Handler) GetServices(ctx context.Context) (map[string]models.Service, e
 		if !ok {
 			continue
 		}
-		if _, ok = h.hostBlacklist[kService.Host]; ok {
+		route := newRoute(kRoute)
+		var extPaths, deniedPaths []string
+		for _, rPath := range kRoute.Paths {
+			extPath, err := parseRoutePath(rPath, route.StripPath)
+			if err != nil {
+				logger.Warn("skipping route path", slog_attr.HostKey, kService.Host, slog_attr.PortKey, kService.Port, slog_attr.RouteKey, kRoute.Name, slog_attr.PathKey, rPath, attributes.ErrorKey, err)
+				continue
+			}
+			input := ruleInput{
+				host:     kService.Host,
+				port:     kService.Port,
+				protocol: kService.Protocol,
+				service:  kService.Name,
+				route:    kRoute.Name,
+				path:     extPath,
+			}
+			if !isAllowed(h.rules, input) {
+				logger.Debug("route path denied by discovery rules", slog_attr.HostKey, kService.Host, slog_attr.PortKey, kService.Port, slog_attr.RouteKey, kRoute.Name, slog_attr.PathKey, extPath)
+				deniedPaths = append(deniedPaths, extPath)
+				continue
+			}
+			extPaths = append(extPaths, extPath)
+		}
+		if len(extPaths) == 0 && len(deniedPaths) == 0 {
 			continue
 		}
-		id := fmt.Sprintf("%s%d", kService.Host, kService.Port)
+		id := getServiceID(kService)
 		service, ok := services[id]
 		if !ok {
 			service.ID = id
 			service.Host = kService.Host
 			service.Port = kService.Port
 			service.Protocol = kService.Protocol
+			service.Workspace = kService.Workspace
+			service.Routes = make(map[string]models.Route)
+		}
+		for _, extPath := range extPaths {
+			if r, ok := service.Routes[extPath]; ok {
+				service.Routes[extPath] = mergeRoutes(r, route)
+				continue
+			}
+			service.ExtPaths = append(service.ExtPaths, extPath)
+			service.Routes[extPath] = route
+		}
+		for _, extPath := range deniedPaths {
+			if !slices.Contains(service.DeniedPaths, extPath) {
+				service.DeniedPaths = append(service.DeniedPaths, extPath)
+			}
+		}
+		if _, ok = optsSet[kService.ID]; !ok {
+			opts, err := parseDocOptions(kService.Tags)
+			if err != nil {
+				logger.Warn("parsing doc options failed", slog_attr.HostKey, kService.Host, slog_attr.PortKey, kService.Port, attributes.ErrorKey, err)
+			}
+			service.DocOptions = util.MergeDocOptions(service.DocOptions, opts)
+			optsSet[kService.ID] = struct{}{}
 		}
-		service.ExtPaths = append(service.ExtPaths, kRoute.Paths...)
 		services[id] = service
 	}
-	for _, service := range services {
-		logger.Debug("found service", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, slog_attr.ExternalPathsKey, service.ExtPaths)
+	for id, service := range services {
+		service.DeniedPaths = slices.DeleteFunc(service.DeniedPaths, func(extPath string) bool {
+			return slices.Contains(service.ExtPaths, extPath)
+		})
+		service.Denied = len(service.ExtPaths) == 0
+		services[id] = service
+		logger.Debug("found service", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, slog_attr.ExternalPathsKey, service.ExtPaths, slog_attr.DocOptionsKey, service.DocOptions)
 	}
 	return services, nil
 }
 
+func getServiceID(kService kong_clt.Service) string {
+	if kService.Workspace == "" || kService.Workspace == defaultWorkspace {
+		return fmt.Sprintf("%s%d", kService.Host, kService.Port)
+	}
+	return fmt.Sprintf("%s_%s%d", kService.Workspace, kService.Host, kService.Port)
+}
+
 func getKongSrvMap(kServices []kong_clt.Service) map[string]kong_clt.Service {
 	srvMap := make(map[string]kong_clt.Service)
 	for _, kService := range kServices {
@@ -94,3 +154,32 @@ func getKongSrvMap(kServices []kong_clt.Service) map[string]kong_clt.Service {
 	}
 	return srvMap
 }
+
+func newRoute(kRoute kong_clt.Route) models.Route {
+	route := models.Route{
+		StripPath: kRoute.StripPath == nil || *kRoute.StripPath,
+	}
+	for _, method := range kRoute.Methods {
+		route.Methods = append(route.Methods, strings.ToUpper(method))
+	}
+	route.Hosts = append(route.Hosts, kRoute.Hosts...)
+	return route
+}
+
+func mergeRoutes(a, b models.Route) models.Route {
+	a.Methods = mergeRouteValues(a.Methods, b.Methods)
+	a.Hosts = mergeRouteValues(a.Hosts, b.Hosts)
+	return a
+}
+
+func mergeRouteValues(a, b []string) []string {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	for _, val := range b {
+		if !slices.Contains(a, val) {
+			a = append(a, val)
+		}
+	}
+	return a
+}
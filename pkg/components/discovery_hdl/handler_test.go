@@ -26,15 +26,19 @@ import (
 	"os"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestHandler_GetServices(t *testing.T) {
+	stripPath := false
 	mockClt := &mockClient{
 		Routes: []kong_clt.Route{
 			{
-				Name:  "route-a",
-				ID:    "r1",
-				Paths: []string{"/a/a", "/a/b"},
+				Name:      "route-a",
+				ID:        "r1",
+				Paths:     []string{"/a/a", "/a/b"},
+				Methods:   []string{"get", "POST"},
+				StripPath: &stripPath,
 				Service: struct {
 					ID string `json:"id"`
 				}{ID: "s1"},
@@ -43,18 +47,38 @@ func TestHandler_GetServices(t *testing.T) {
 				Name:  "route-b",
 				ID:    "r2",
 				Paths: []string{"/c"},
+				Hosts: []string{"api.test"},
 				Service: struct {
 					ID string `json:"id"`
 				}{ID: "s2"},
 			},
 			{
-				Name:  "route-c",
-				ID:    "r3",
-				Paths: []string{"/d"},
+				Name:    "route-c",
+				ID:      "r3",
+				Paths:   []string{"/d", "/c"},
+				Methods: []string{"GET"},
+				Hosts:   []string{"*.test"},
 				Service: struct {
 					ID string `json:"id"`
 				}{ID: "s2"},
 			},
+			{
+				Name:      "route-e",
+				ID:        "r5",
+				Paths:     []string{"~/e/v[0-9]+/", "~/f$", "~(a|b)", "~/g/("},
+				StripPath: &stripPath,
+				Service: struct {
+					ID string `json:"id"`
+				}{ID: "s1"},
+			},
+			{
+				Name:  "route-f",
+				ID:    "r6",
+				Paths: []string{"~/h/[a-z]+"},
+				Service: struct {
+					ID string `json:"id"`
+				}{ID: "s1"},
+			},
 			{
 				Name:  "route-d",
 				ID:    "r4",
@@ -66,16 +90,18 @@ func TestHandler_GetServices(t *testing.T) {
 		},
 		Services: []kong_clt.Service{
 			{
-				Host:     "api.srv-a",
-				Protocol: "http",
-				ID:       "s1",
-				Port:     8000,
+				Host:      "api.srv-a",
+				Protocol:  "http",
+				ID:        "s1",
+				Port:      8000,
+				Workspace: "ws-a",
 			},
 			{
 				Host:     "api.srv-b",
 				Protocol: "https",
 				ID:       "s2",
 				Port:     8080,
+				Tags:     []string{"test", "api-docs:path=/openapi.json", "api-docs:timeout=10s", "api-docs:protocol=ftp"},
 			},
 			{
 				Host:     "api.srv-c",
@@ -87,14 +113,21 @@ func TestHandler_GetServices(t *testing.T) {
 	}
 	util.InitLogger(struct_logger.Config{}, os.Stderr, "", "")
 	InitLogger()
-	hdl := New(mockClt, 0, []string{"api.srv-c"})
+	hdl := New(mockClt, 0, NewHostBlacklistRules([]string{"api.srv-c"}))
 	a := map[string]models.Service{
-		"api.srv-a8000": {
-			ID:       "api.srv-a8000",
-			Host:     "api.srv-a",
-			Port:     8000,
-			Protocol: "http",
-			ExtPaths: []string{"/a/a", "/a/b"},
+		"ws-a_api.srv-a8000": {
+			ID:        "ws-a_api.srv-a8000",
+			Host:      "api.srv-a",
+			Port:      8000,
+			Protocol:  "http",
+			Workspace: "ws-a",
+			ExtPaths:  []string{"/a/a", "/a/b", "/e", "/f"},
+			Routes: map[string]models.Route{
+				"/a/a": {Methods: []string{"GET", "POST"}},
+				"/a/b": {Methods: []string{"GET", "POST"}},
+				"/e":   {},
+				"/f":   {},
+			},
 		},
 		"api.srv-b8080": {
 			ID:       "api.srv-b8080",
@@ -102,6 +135,23 @@ func TestHandler_GetServices(t *testing.T) {
 			Port:     8080,
 			Protocol: "https",
 			ExtPaths: []string{"/c", "/d"},
+			Routes: map[string]models.Route{
+				"/c": {Hosts: []string{"api.test", "*.test"}, StripPath: true},
+				"/d": {Methods: []string{"GET"}, Hosts: []string{"*.test"}, StripPath: true},
+			},
+			DocOptions: models.DocOptions{
+				Path:    "/openapi.json",
+				Timeout: time.Second * 10,
+			},
+		},
+		"api.srv-c80": {
+			ID:          "api.srv-c80",
+			Host:        "api.srv-c",
+			Port:        80,
+			Protocol:    "https",
+			Denied:      true,
+			DeniedPaths: []string{"/e"},
+			Routes:      map[string]models.Route{},
 		},
 	}
 	b, err := hdl.GetServices(context.Background())
@@ -111,6 +161,38 @@ func TestHandler_GetServices(t *testing.T) {
 	if !reflect.DeepEqual(a, b) {
 		t.Errorf("expected: %v, got: %v", a, b)
 	}
+	t.Run("workspaces", func(t *testing.T) {
+		hdl := New(&mockClient{
+			Routes: []kong_clt.Route{
+				{ID: "r1", Paths: []string{"/a"}, Service: struct {
+					ID string `json:"id"`
+				}{ID: "s1"}, Workspace: "ws-a"},
+				{ID: "r2", Paths: []string{"/b"}, Service: struct {
+					ID string `json:"id"`
+				}{ID: "s2"}, Workspace: "ws-b"},
+				{ID: "r3", Paths: []string{"/c"}, Service: struct {
+					ID string `json:"id"`
+				}{ID: "s3"}, Workspace: "default"},
+			},
+			Services: []kong_clt.Service{
+				{ID: "s1", Host: "api", Port: 80, Protocol: "http", Workspace: "ws-a"},
+				{ID: "s2", Host: "api", Port: 80, Protocol: "http", Workspace: "ws-b"},
+				{ID: "s3", Host: "api", Port: 80, Protocol: "http", Workspace: "default"},
+			},
+		}, 0, nil)
+		a := map[string]models.Service{
+			"ws-a_api80": {ID: "ws-a_api80", Host: "api", Port: 80, Protocol: "http", Workspace: "ws-a", ExtPaths: []string{"/a"}, Routes: map[string]models.Route{"/a": {StripPath: true}}},
+			"ws-b_api80": {ID: "ws-b_api80", Host: "api", Port: 80, Protocol: "http", Workspace: "ws-b", ExtPaths: []string{"/b"}, Routes: map[string]models.Route{"/b": {StripPath: true}}},
+			"api80":      {ID: "api80", Host: "api", Port: 80, Protocol: "http", Workspace: "default", ExtPaths: []string{"/c"}, Routes: map[string]models.Route{"/c": {StripPath: true}}},
+		}
+		b, err := hdl.GetServices(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("expected: %v, got: %v", a, b)
+		}
+	})
 	t.Run("error", func(t *testing.T) {
 		t.Run("get routes", func(t *testing.T) {
 			mockClt.GetRoutesErr = errors.New("error")
@@ -131,6 +213,97 @@ func TestHandler_GetServices(t *testing.T) {
 	})
 }
 
+func Test_parseDocOptions(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		a := models.DocOptions{
+			Path:     "/v3/api-docs",
+			Protocol: "https",
+			Timeout:  time.Second * 5,
+			Disabled: true,
+		}
+		b, err := parseDocOptions([]string{"test", "api-docs:path=/v3/api-docs", "api-docs:protocol=https", "api-docs:timeout=5s", "api-docs:disabled"})
+		if err != nil {
+			t.Error(err)
+		}
+		if a != b {
+			t.Errorf("expected: %v, got: %v", a, b)
+		}
+	})
+	t.Run("no options", func(t *testing.T) {
+		b, err := parseDocOptions([]string{"test", "api-docs"})
+		if err != nil {
+			t.Error(err)
+		}
+		if b != (models.DocOptions{}) {
+			t.Errorf("expected empty options, got: %v", b)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for _, tag := range []string{
+			"api-docs:path=doc",
+			"api-docs:protocol=ftp",
+			"api-docs:timeout=test",
+			"api-docs:timeout=-1s",
+			"api-docs:disabled=false",
+			"api-docs:test",
+		} {
+			b, err := parseDocOptions([]string{"api-docs:path=/doc", tag})
+			if err == nil {
+				t.Errorf("expected error for '%s'", tag)
+			}
+			if b.Path != "/doc" {
+				t.Errorf("expected valid options to be kept for '%s'", tag)
+			}
+		}
+	})
+}
+
+func Test_parseRoutePath(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		tests := []struct {
+			path      string
+			stripPath bool
+			expected  string
+		}{
+			{"/a/b", true, "/a/b"},
+			{"/a/b", false, "/a/b"},
+			{"~/a/b", true, "/a/b"},
+			{"~^/a/b", true, "/a/b"},
+			{"~/a/b$", true, "/a/b"},
+			{"~/a/b/(?<id>[0-9]+)", false, "/a/b"},
+			{"~/a/v[0-9]+/b", false, "/a"},
+			{"~/a/(x|y)", false, "/a"},
+			{"~/(x|y)", false, "/"},
+		}
+		for _, tc := range tests {
+			b, err := parseRoutePath(tc.path, tc.stripPath)
+			if err != nil {
+				t.Errorf("'%s': %s", tc.path, err)
+				continue
+			}
+			if b != tc.expected {
+				t.Errorf("'%s': expected '%s', got '%s'", tc.path, tc.expected, b)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			path      string
+			stripPath bool
+		}{
+			{"~/a/(", false},
+			{"~(x|y)/a", false},
+			{"~.*", false},
+			{"~/a/[0-9]+", true},
+		}
+		for _, tc := range tests {
+			if _, err := parseRoutePath(tc.path, tc.stripPath); err == nil {
+				t.Errorf("'%s': expected error", tc.path)
+			}
+		}
+	})
+}
+
 func Test_getKongSrvMap(t *testing.T) {
 	a := map[string]kong_clt.Service{
 		"1": {
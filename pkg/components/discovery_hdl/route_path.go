@@ -0,0 +1,60 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package discovery_hdl
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const regexPathPrefix = "~"
+
+func parseRoutePath(pth string, stripPath bool) (string, error) {
+	expr, ok := strings.CutPrefix(pth, regexPathPrefix)
+	if !ok {
+		return pth, nil
+	}
+	expr = strings.TrimPrefix(expr, "^")
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return "", fmt.Errorf("invalid regex path: %w", err)
+	}
+	prefix, complete := re.LiteralPrefix()
+	if !complete {
+		if lit, ok := strings.CutSuffix(expr, "$"); ok {
+			if re, err = regexp.Compile(lit); err == nil {
+				prefix, complete = re.LiteralPrefix()
+			}
+		}
+	}
+	if !strings.HasPrefix(prefix, "/") {
+		return "", errors.New("regex path without literal prefix")
+	}
+	if complete {
+		return prefix, nil
+	}
+	if stripPath {
+		return "", errors.New("regex path strips a variable part")
+	}
+	prefix = prefix[:strings.LastIndex(prefix, "/")+1]
+	if len(prefix) > 1 {
+		prefix = strings.TrimSuffix(prefix, "/")
+	}
+	return prefix, nil
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package discovery_hdl
+
+import (
+	"fmt"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+	"path"
+	"strings"
+)
+
+type ruleInput struct {
+	host     string
+	port     int
+	protocol string
+	service  string
+	route    string
+	path     string
+}
+
+func NewHostBlacklistRules(hosts []string) []models.DiscoveryRule {
+	var rules []models.DiscoveryRule
+	for _, host := range hosts {
+		rules = append(rules, models.DiscoveryRule{Action: models.DiscoveryRuleDeny, Host: host})
+	}
+	return rules
+}
+
+func ValidateRules(rules []models.DiscoveryRule) error {
+	for i, rule := range rules {
+		if rule.Action != models.DiscoveryRuleAllow && rule.Action != models.DiscoveryRuleDeny {
+			return fmt.Errorf("rule %d: invalid action '%s'", i, rule.Action)
+		}
+		if _, err := path.Match(rule.Host, ""); err != nil {
+			return fmt.Errorf("rule %d: invalid host pattern '%s': %w", i, rule.Host, err)
+		}
+		if rule.Path != "" && !strings.HasPrefix(rule.Path, "/") {
+			return fmt.Errorf("rule %d: invalid path prefix '%s'", i, rule.Path)
+		}
+	}
+	return nil
+}
+
+func HasKongOnlyRules(rules []models.DiscoveryRule) bool {
+	for _, rule := range rules {
+		if rule.Service != "" || rule.Route != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func FilterServices(rules []models.DiscoveryRule, services map[string]models.Service) map[string]models.Service {
+	if len(rules) == 0 {
+		return services
+	}
+	filtered := make(map[string]models.Service)
+	for id, service := range services {
+		if len(service.ExtPaths) == 0 {
+			service.Denied = !isAllowed(rules, ruleInput{host: service.Host, port: service.Port, protocol: service.Protocol})
+			filtered[id] = service
+			continue
+		}
+		var extPaths, deniedPaths []string
+		routes := make(map[string]models.Route)
+		for _, extPath := range service.ExtPaths {
+			input := ruleInput{
+				host:     service.Host,
+				port:     service.Port,
+				protocol: service.Protocol,
+				path:     extPath,
+			}
+			if !isAllowed(rules, input) {
+				deniedPaths = append(deniedPaths, extPath)
+				continue
+			}
+			extPaths = append(extPaths, extPath)
+			if route, ok := service.Routes[extPath]; ok {
+				routes[extPath] = route
+			}
+		}
+		service.ExtPaths = extPaths
+		service.DeniedPaths = deniedPaths
+		service.Denied = len(extPaths) == 0
+		if service.Routes != nil {
+			service.Routes = routes
+		}
+		filtered[id] = service
+	}
+	return filtered
+}
+
+func isAllowed(rules []models.DiscoveryRule, input ruleInput) bool {
+	for _, rule := range rules {
+		if matchRule(rule, input) {
+			return rule.Action == models.DiscoveryRuleAllow
+		}
+	}
+	return true
+}
+
+func matchRule(rule models.DiscoveryRule, input ruleInput) bool {
+	if rule.Host != "" {
+		if ok, _ := path.Match(strings.ToLower(rule.Host), strings.ToLower(input.host)); !ok {
+			return false
+		}
+	}
+	if rule.Port > 0 && rule.Port != input.port {
+		return false
+	}
+	if rule.Protocol != "" && !strings.EqualFold(rule.Protocol, input.protocol) {
+		return false
+	}
+	if rule.Service != "" && rule.Service != input.service {
+		return false
+	}
+	if rule.Route != "" && rule.Route != input.route {
+		return false
+	}
+	if rule.Path != "" && !hasPathPrefix(input.path, rule.Path) {
+		return false
+	}
+	return true
+}
+
+func hasPathPrefix(pth, prefix string) bool {
+	if pth == prefix || prefix == "/" {
+		return true
+	}
+	return strings.HasPrefix(pth, strings.TrimSuffix(prefix, "/")+"/")
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package discovery_hdl
+
+import (
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+	"reflect"
+	"testing"
+)
+
+func Test_isAllowed(t *testing.T) {
+	rules := []models.DiscoveryRule{
+		{Action: models.DiscoveryRuleAllow, Host: "public-internal", Path: "/docs"},
+		{Action: models.DiscoveryRuleDeny, Host: "*-internal"},
+		{Action: models.DiscoveryRuleDeny, Path: "/admin"},
+		{Action: models.DiscoveryRuleDeny, Port: 9000, Protocol: "https"},
+		{Action: models.DiscoveryRuleDeny, Service: "srv-x"},
+		{Action: models.DiscoveryRuleDeny, Service: "srv-y", Route: "route-y"},
+	}
+	tests := []struct {
+		input    ruleInput
+		expected bool
+	}{
+		{ruleInput{host: "api", port: 80, protocol: "http", path: "/a"}, true},
+		{ruleInput{host: "api-internal", port: 80, protocol: "http", path: "/a"}, false},
+		{ruleInput{host: "API-INTERNAL", port: 80, protocol: "http", path: "/a"}, false},
+		{ruleInput{host: "public-internal", port: 80, protocol: "http", path: "/docs/a"}, true},
+		{ruleInput{host: "public-internal", port: 80, protocol: "http", path: "/doc"}, false},
+		{ruleInput{host: "api", port: 80, protocol: "http", path: "/admin"}, false},
+		{ruleInput{host: "api", port: 80, protocol: "http", path: "/admin/a"}, false},
+		{ruleInput{host: "api", port: 80, protocol: "http", path: "/administration"}, true},
+		{ruleInput{host: "api", port: 9000, protocol: "https", path: "/a"}, false},
+		{ruleInput{host: "api", port: 9000, protocol: "http", path: "/a"}, true},
+		{ruleInput{host: "api", port: 80, protocol: "http", service: "srv-x", path: "/a"}, false},
+		{ruleInput{host: "api", port: 80, protocol: "http", service: "srv-y", route: "route-y", path: "/a"}, false},
+		{ruleInput{host: "api", port: 80, protocol: "http", service: "srv-y", route: "route-z", path: "/a"}, true},
+	}
+	for _, tc := range tests {
+		if b := isAllowed(rules, tc.input); b != tc.expected {
+			t.Errorf("%+v: expected %v, got %v", tc.input, tc.expected, b)
+		}
+	}
+	t.Run("no rules", func(t *testing.T) {
+		if !isAllowed(nil, ruleInput{host: "api", path: "/a"}) {
+			t.Error("expected true")
+		}
+	})
+	t.Run("root prefix", func(t *testing.T) {
+		if isAllowed([]models.DiscoveryRule{{Action: models.DiscoveryRuleDeny, Path: "/"}}, ruleInput{host: "api", path: "/a"}) {
+			t.Error("expected false")
+		}
+	})
+}
+
+func TestValidateRules(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		err := ValidateRules([]models.DiscoveryRule{
+			{Action: models.DiscoveryRuleAllow, Host: "api-*"},
+			{Action: models.DiscoveryRuleDeny, Path: "/admin"},
+		})
+		if err != nil {
+			t.Error(err)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		tests := [][]models.DiscoveryRule{
+			{{Action: "test"}},
+			{{Action: models.DiscoveryRuleDeny, Host: "[a"}},
+			{{Action: models.DiscoveryRuleDeny, Path: "admin"}},
+		}
+		for _, tc := range tests {
+			if err := ValidateRules(tc); err == nil {
+				t.Errorf("%+v: expected error", tc)
+			}
+		}
+	})
+}
+
+func TestHasKongOnlyRules(t *testing.T) {
+	if HasKongOnlyRules([]models.DiscoveryRule{{Action: models.DiscoveryRuleDeny, Host: "a", Path: "/admin"}}) {
+		t.Error("expected false")
+	}
+	if !HasKongOnlyRules([]models.DiscoveryRule{{Action: models.DiscoveryRuleDeny, Host: "a"}, {Action: models.DiscoveryRuleDeny, Service: "srv-a"}}) {
+		t.Error("expected true")
+	}
+	if !HasKongOnlyRules([]models.DiscoveryRule{{Action: models.DiscoveryRuleAllow, Route: "route-a"}}) {
+		t.Error("expected true")
+	}
+}
+
+func TestFilterServices(t *testing.T) {
+	services := map[string]models.Service{
+		"a80": {ID: "a80", Host: "a", Port: 80, ExtPaths: []string{"/x", "/admin"}, Routes: map[string]models.Route{"/x": {StripPath: true}, "/admin": {}}},
+		"b80": {ID: "b80", Host: "b-internal", Port: 80, ExtPaths: []string{"/y"}},
+		"c80": {ID: "c80", Host: "c-internal", Port: 80},
+		"d80": {ID: "d80", Host: "d", Port: 80, ExtPaths: []string{"/admin"}},
+	}
+	rules := []models.DiscoveryRule{
+		{Action: models.DiscoveryRuleDeny, Host: "*-internal"},
+		{Action: models.DiscoveryRuleDeny, Path: "/admin"},
+	}
+	a := map[string]models.Service{
+		"a80": {ID: "a80", Host: "a", Port: 80, ExtPaths: []string{"/x"}, DeniedPaths: []string{"/admin"}, Routes: map[string]models.Route{"/x": {StripPath: true}}},
+		"b80": {ID: "b80", Host: "b-internal", Port: 80, Denied: true, DeniedPaths: []string{"/y"}},
+		"c80": {ID: "c80", Host: "c-internal", Port: 80, Denied: true},
+		"d80": {ID: "d80", Host: "d", Port: 80, Denied: true, DeniedPaths: []string{"/admin"}},
+	}
+	b := FilterServices(rules, services)
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("expected %v, got %v", a, b)
+	}
+	t.Run("no rules", func(t *testing.T) {
+		if b := FilterServices(nil, services); !reflect.DeepEqual(services, b) {
+			t.Errorf("expected %v, got %v", services, b)
+		}
+	})
+}
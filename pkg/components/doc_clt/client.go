@@ -20,56 +20,84 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util"
 	base_client "github.com/SENERGY-Platform/go-base-http-client"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 )
 
 type ClientItf interface {
-	GetDoc(ctx context.Context, protocol, host string, port int) ([]byte, error)
+	GetDoc(ctx context.Context, protocol, host string, port int, docPath string, validators Validators) (Doc, error)
 }
 
 type Client struct {
-	baseClient *base_client.Client
-	docPath    string
+	httpClient base_client.HTTPClient
 }
 
-func New(httpClient base_client.HTTPClient, docPath string) *Client {
+func New(httpClient base_client.HTTPClient) *Client {
 	return &Client{
-		baseClient: base_client.New(httpClient, customError, ""),
-		docPath:    docPath,
+		httpClient: httpClient,
 	}
 }
 
-func (h *Client) GetDoc(ctx context.Context, protocol, host string, port int) ([]byte, error) {
+func (h *Client) GetDoc(ctx context.Context, protocol, host string, port int, docPath string, validators Validators) (Doc, error) {
 	baseUrl := fmt.Sprintf("%s://%s", protocol, host)
 	if port > 0 {
 		baseUrl = baseUrl + fmt.Sprintf(":%d", port)
 	}
-	u, err := url.JoinPath(baseUrl, h.docPath)
+	u, err := url.JoinPath(baseUrl, docPath)
 	if err != nil {
-		return nil, err
+		return Doc{}, err
 	}
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
 	if err != nil {
-		return nil, err
+		return Doc{}, err
 	}
-	body, err := h.baseClient.ExecRequest(req)
+	req.Header.Set("Accept", "application/json, application/yaml;q=0.9, */*;q=0.8")
+	if validators.ETag != "" {
+		req.Header.Set("If-None-Match", validators.ETag)
+	}
+	if validators.LastModified != "" {
+		req.Header.Set("If-Modified-Since", validators.LastModified)
+	}
+	resp, err := h.httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		return Doc{}, err
 	}
-	defer body.Close()
-	b, err := io.ReadAll(body)
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return Doc{}, ErrNotModified
+	}
+	b, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return Doc{}, err
+	}
+	if resp.StatusCode >= 400 {
+		msg := strings.TrimSpace(string(b))
+		if msg == "" {
+			msg = resp.Status
+		}
+		return Doc{}, NewResponseError(resp.StatusCode, errors.New(msg))
 	}
 	if len(b) == 0 {
-		return nil, errors.New("empty response")
+		return Doc{}, errors.New("empty response")
 	}
-	return b, nil
-}
-
-func customError(_ int, err error) error {
-	return err
+	doc := Doc{
+		Format: util.DetectDocFormat(resp.Header.Get("Content-Type"), b),
+		Validators: Validators{
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+		},
+	}
+	if doc.Format == models.DocFormatYAML {
+		if b, err = util.YAMLToJSON(b); err != nil {
+			return Doc{}, err
+		}
+	}
+	doc.Data = b
+	return doc, nil
 }
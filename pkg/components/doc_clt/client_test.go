@@ -0,0 +1,94 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package doc_clt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+func TestClient_GetDoc(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/doc":
+			if r.Header.Get("If-None-Match") == `"a"` || r.Header.Get("If-Modified-Since") == "Mon, 02 Jan 2006 15:04:05 GMT" {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("ETag", `"a"`)
+			w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+			_, _ = w.Write([]byte(`{"swagger": "2.0"}`))
+		case "/yaml":
+			w.Header().Set("Content-Type", "application/yaml")
+			_, _ = w.Write([]byte("swagger: \"2.0\"\n"))
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := strconv.Atoi(u.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+	clt := New(server.Client())
+	t.Run("validators", func(t *testing.T) {
+		doc, err := clt.GetDoc(context.Background(), "http", u.Hostname(), port, "/doc", Validators{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		a := Validators{ETag: `"a"`, LastModified: "Mon, 02 Jan 2006 15:04:05 GMT"}
+		if doc.Validators != a {
+			t.Errorf("expected %v, got %v", a, doc.Validators)
+		}
+		if string(doc.Data) != `{"swagger": "2.0"}` {
+			t.Errorf("unexpected data '%s'", doc.Data)
+		}
+	})
+	t.Run("not modified", func(t *testing.T) {
+		for _, v := range []Validators{{ETag: `"a"`}, {LastModified: "Mon, 02 Jan 2006 15:04:05 GMT"}} {
+			if _, err := clt.GetDoc(context.Background(), "http", u.Hostname(), port, "/doc", v); !errors.Is(err, ErrNotModified) {
+				t.Errorf("%v: expected not modified, got %v", v, err)
+			}
+		}
+	})
+	t.Run("yaml", func(t *testing.T) {
+		doc, err := clt.GetDoc(context.Background(), "http", u.Hostname(), port, "/yaml", Validators{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(doc.Data) != `{"swagger":"2.0"}` {
+			t.Errorf("unexpected data '%s'", doc.Data)
+		}
+	})
+	t.Run("error", func(t *testing.T) {
+		_, err := clt.GetDoc(context.Background(), "http", u.Hostname(), port, "/test", Validators{})
+		var respErr *ResponseError
+		if !errors.As(err, &respErr) || respErr.Code != http.StatusServiceUnavailable {
+			t.Errorf("expected response error, got %v", err)
+		}
+	})
+}
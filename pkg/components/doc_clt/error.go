@@ -0,0 +1,38 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package doc_clt
+
+import "errors"
+
+var ErrNotModified = errors.New("not modified")
+
+type ResponseError struct {
+	Code int
+	err  error
+}
+
+func NewResponseError(code int, err error) *ResponseError {
+	return &ResponseError{Code: code, err: err}
+}
+
+func (e *ResponseError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ResponseError) Unwrap() error {
+	return e.err
+}
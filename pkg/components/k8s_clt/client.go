@@ -0,0 +1,129 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package k8s_clt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	base_client "github.com/SENERGY-Platform/go-base-http-client"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+)
+
+type ClientItf interface {
+	GetServices(ctx context.Context, namespace string) ([]Service, error)
+	GetIngresses(ctx context.Context, namespace string) ([]Ingress, error)
+}
+
+type Client struct {
+	baseClient *base_client.Client
+	baseUrl    string
+	tokenPath  string
+	pageSize   int
+}
+
+func New(httpClient base_client.HTTPClient, baseUrl, tokenPath string, pageSize int) *Client {
+	return &Client{
+		baseClient: base_client.New(httpClient, customError, ""),
+		baseUrl:    baseUrl,
+		tokenPath:  tokenPath,
+		pageSize:   pageSize,
+	}
+}
+
+func (c *Client) GetServices(ctx context.Context, namespace string) ([]Service, error) {
+	return getAll[Service](ctx, c, "api/v1", namespace, "services")
+}
+
+func (c *Client) GetIngresses(ctx context.Context, namespace string) ([]Ingress, error) {
+	return getAll[Ingress](ctx, c, "apis/networking.k8s.io/v1", namespace, "ingresses")
+}
+
+func getAll[T any](ctx context.Context, c *Client, group, namespace, resource string) ([]T, error) {
+	elems := []string{group}
+	if namespace != "" {
+		elems = append(elems, "namespaces", namespace)
+	}
+	u, err := url.JoinPath(c.baseUrl, append(elems, resource)...)
+	if err != nil {
+		return nil, err
+	}
+	var items []T
+	var cont string
+	for {
+		resp, err := getList[T](ctx, c, u, cont)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, resp.Items...)
+		if resp.Metadata.Continue == "" {
+			break
+		}
+		if resp.Metadata.Continue == cont {
+			return nil, errors.New("repeated continue token")
+		}
+		cont = resp.Metadata.Continue
+	}
+	return items, nil
+}
+
+func getList[T any](ctx context.Context, c *Client, u, cont string) (list[T], error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return list[T]{}, err
+	}
+	query := req.URL.Query()
+	if c.pageSize > 0 {
+		query.Set("limit", strconv.Itoa(c.pageSize))
+	}
+	if cont != "" {
+		query.Set("continue", cont)
+	}
+	req.URL.RawQuery = query.Encode()
+	if err = c.setBearerToken(req); err != nil {
+		return list[T]{}, err
+	}
+	var resp list[T]
+	if err = c.baseClient.ExecRequestJSON(req, &resp); err != nil {
+		return list[T]{}, err
+	}
+	return resp, nil
+}
+
+func (c *Client) setBearerToken(req *http.Request) error {
+	if c.tokenPath == "" {
+		return nil
+	}
+	b, err := os.ReadFile(c.tokenPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if token := bytes.TrimSpace(b); len(token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+string(token))
+	}
+	return nil
+}
+
+func customError(_ int, err error) error {
+	return err
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package k8s_clt
+
+type Metadata struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+type ServicePort struct {
+	Name     string `json:"name"`
+	Port     int    `json:"port"`
+	Protocol string `json:"protocol"`
+}
+
+type Service struct {
+	Metadata Metadata `json:"metadata"`
+	Spec     struct {
+		Ports []ServicePort `json:"ports"`
+	} `json:"spec"`
+}
+
+type IngressServiceBackend struct {
+	Name string `json:"name"`
+	Port struct {
+		Name   string `json:"name"`
+		Number int    `json:"number"`
+	} `json:"port"`
+}
+
+type IngressPath struct {
+	Path     string `json:"path"`
+	PathType string `json:"pathType"`
+	Backend  struct {
+		Service *IngressServiceBackend `json:"service"`
+	} `json:"backend"`
+}
+
+type IngressRule struct {
+	Host string `json:"host"`
+	HTTP *struct {
+		Paths []IngressPath `json:"paths"`
+	} `json:"http"`
+}
+
+type Ingress struct {
+	Metadata Metadata `json:"metadata"`
+	Spec     struct {
+		Rules []IngressRule `json:"rules"`
+	} `json:"spec"`
+}
+
+type list[T any] struct {
+	Metadata struct {
+		Continue string `json:"continue"`
+	} `json:"metadata"`
+	Items []T `json:"items"`
+}
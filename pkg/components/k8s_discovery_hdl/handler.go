@@ -0,0 +1,260 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package k8s_discovery_hdl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	lib_models "github.com/SENERGY-Platform/api-docs-provider/lib/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/components/discovery_hdl"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/components/k8s_clt"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util/slog_attr"
+	"github.com/SENERGY-Platform/go-service-base/struct-logger/attributes"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	annotationPrefix    = "api-docs/"
+	annotationPaths     = annotationPrefix + "paths"
+	annotationPort      = annotationPrefix + "port"
+	annotationMethods   = annotationPrefix + "methods"
+	annotationStripPath = annotationPrefix + "strip-path"
+	annotationEnabled   = annotationPrefix + "enabled"
+)
+
+var docOptionKeys = []string{
+	util.DocOptionPath,
+	util.DocOptionDisabled,
+	util.DocOptionProtocol,
+	util.DocOptionTimeout,
+}
+
+type Handler struct {
+	client     k8s_clt.ClientItf
+	namespaces []string
+	timeout    time.Duration
+	rules      []models.DiscoveryRule
+}
+
+func New(client k8s_clt.ClientItf, namespaces []string, timeout time.Duration, rules []models.DiscoveryRule) *Handler {
+	if len(namespaces) == 0 {
+		namespaces = []string{""}
+	}
+	return &Handler{
+		client:     client,
+		namespaces: namespaces,
+		timeout:    timeout,
+		rules:      rules,
+	}
+}
+
+func (h *Handler) GetServices(ctx context.Context) (map[string]models.Service, error) {
+	ctxWt, cf := context.WithTimeout(ctx, h.timeout)
+	defer cf()
+	var kServices []k8s_clt.Service
+	var kIngresses []k8s_clt.Ingress
+	for _, namespace := range h.namespaces {
+		items, err := h.client.GetServices(ctxWt, namespace)
+		if err != nil {
+			return nil, lib_models.NewInternalError(err)
+		}
+		kServices = append(kServices, items...)
+		ingresses, err := h.client.GetIngresses(ctxWt, namespace)
+		if err != nil {
+			return nil, lib_models.NewInternalError(err)
+		}
+		kIngresses = append(kIngresses, ingresses...)
+	}
+	kSrvMap := make(map[string]k8s_clt.Service)
+	for _, kService := range kServices {
+		kSrvMap[getKey(kService.Metadata.Namespace, kService.Metadata.Name)] = kService
+	}
+	services := make(map[string]models.Service)
+	for _, kService := range kServices {
+		val, ok := kService.Metadata.Annotations[annotationPaths]
+		if !ok {
+			continue
+		}
+		if err := addServiceRoutes(services, kService, val); err != nil {
+			logger.Warn("skipping service", slog_attr.NameKey, kService.Metadata.Name, slog_attr.NamespaceKey, kService.Metadata.Namespace, attributes.ErrorKey, err)
+		}
+	}
+	for _, kIngress := range kIngresses {
+		if enabled, _ := strconv.ParseBool(kIngress.Metadata.Annotations[annotationEnabled]); !enabled {
+			continue
+		}
+		addIngressRoutes(services, kIngress, kSrvMap)
+	}
+	services = discovery_hdl.FilterServices(h.rules, services)
+	for _, service := range services {
+		logger.Debug("found service", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, slog_attr.ExternalPathsKey, service.ExtPaths, slog_attr.DocOptionsKey, service.DocOptions)
+	}
+	return services, nil
+}
+
+func addServiceRoutes(services map[string]models.Service, kService k8s_clt.Service, paths string) error {
+	port, err := getServicePort(kService, kService.Metadata.Annotations[annotationPort])
+	if err != nil {
+		return err
+	}
+	route := models.Route{StripPath: true}
+	if val, ok := kService.Metadata.Annotations[annotationStripPath]; ok {
+		if route.StripPath, err = strconv.ParseBool(val); err != nil {
+			return fmt.Errorf("invalid annotation '%s': %w", annotationStripPath, err)
+		}
+	}
+	if val, ok := kService.Metadata.Annotations[annotationMethods]; ok {
+		route.Methods = splitList(strings.ToUpper(val))
+	}
+	service := getService(services, kService, port)
+	for _, extPath := range splitList(paths) {
+		if !strings.HasPrefix(extPath, "/") {
+			return fmt.Errorf("invalid path '%s'", extPath)
+		}
+		addRoute(&service, extPath, route)
+	}
+	services[service.ID] = service
+	return nil
+}
+
+func addIngressRoutes(services map[string]models.Service, kIngress k8s_clt.Ingress, kSrvMap map[string]k8s_clt.Service) {
+	for _, rule := range kIngress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, iPath := range rule.HTTP.Paths {
+			backend := iPath.Backend.Service
+			if backend == nil {
+				continue
+			}
+			kService, ok := kSrvMap[getKey(kIngress.Metadata.Namespace, backend.Name)]
+			if !ok {
+				logger.Warn("skipping ingress path", slog_attr.NameKey, kIngress.Metadata.Name, slog_attr.NamespaceKey, kIngress.Metadata.Namespace, slog_attr.PathKey, iPath.Path, attributes.ErrorKey, fmt.Errorf("service '%s' not found", backend.Name))
+				continue
+			}
+			portRef := backend.Port.Name
+			if backend.Port.Number > 0 {
+				portRef = strconv.Itoa(backend.Port.Number)
+			}
+			port, err := getServicePort(kService, portRef)
+			if err != nil {
+				logger.Warn("skipping ingress path", slog_attr.NameKey, kIngress.Metadata.Name, slog_attr.NamespaceKey, kIngress.Metadata.Namespace, slog_attr.PathKey, iPath.Path, attributes.ErrorKey, err)
+				continue
+			}
+			extPath := iPath.Path
+			if extPath == "" {
+				extPath = "/"
+			}
+			route := models.Route{}
+			if rule.Host != "" {
+				route.Hosts = []string{rule.Host}
+			}
+			service := getService(services, kService, port)
+			service.DocOptions = util.MergeDocOptions(service.DocOptions, getDocOptions(kIngress.Metadata))
+			addRoute(&service, extPath, route)
+			services[service.ID] = service
+		}
+	}
+}
+
+func getService(services map[string]models.Service, kService k8s_clt.Service, port k8s_clt.ServicePort) models.Service {
+	host := fmt.Sprintf("%s.%s.svc", kService.Metadata.Name, kService.Metadata.Namespace)
+	id := fmt.Sprintf("%s%d", host, port.Port)
+	service, ok := services[id]
+	if ok {
+		return service
+	}
+	protocol := "http"
+	if port.Name == "https" || port.Port == 443 {
+		protocol = "https"
+	}
+	return models.Service{
+		ID:         id,
+		Host:       host,
+		Port:       port.Port,
+		Protocol:   protocol,
+		Routes:     make(map[string]models.Route),
+		DocOptions: getDocOptions(kService.Metadata),
+	}
+}
+
+func addRoute(service *models.Service, extPath string, route models.Route) {
+	if r, ok := service.Routes[extPath]; ok {
+		if len(r.Hosts) > 0 && len(route.Hosts) > 0 {
+			for _, host := range route.Hosts {
+				if !slices.Contains(r.Hosts, host) {
+					r.Hosts = append(r.Hosts, host)
+				}
+			}
+		} else {
+			r.Hosts = nil
+		}
+		service.Routes[extPath] = r
+		return
+	}
+	service.ExtPaths = append(service.ExtPaths, extPath)
+	service.Routes[extPath] = route
+}
+
+func getServicePort(kService k8s_clt.Service, ref string) (k8s_clt.ServicePort, error) {
+	if len(kService.Spec.Ports) == 0 {
+		return k8s_clt.ServicePort{}, errors.New("service without ports")
+	}
+	if ref == "" {
+		return kService.Spec.Ports[0], nil
+	}
+	for _, port := range kService.Spec.Ports {
+		if port.Name == ref || strconv.Itoa(port.Port) == ref {
+			return port, nil
+		}
+	}
+	return k8s_clt.ServicePort{}, fmt.Errorf("port '%s' not found", ref)
+}
+
+func getDocOptions(metadata k8s_clt.Metadata) models.DocOptions {
+	var opts models.DocOptions
+	for _, key := range docOptionKeys {
+		val, ok := metadata.Annotations[annotationPrefix+key]
+		if !ok {
+			continue
+		}
+		if err := util.SetDocOption(&opts, key, val); err != nil {
+			logger.Warn("parsing doc option failed", slog_attr.NameKey, metadata.Name, slog_attr.NamespaceKey, metadata.Namespace, attributes.ErrorKey, fmt.Errorf("annotation '%s': %w", annotationPrefix+key, err))
+		}
+	}
+	return opts
+}
+
+func splitList(val string) []string {
+	var items []string
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func getKey(namespace, name string) string {
+	return namespace + "/" + name
+}
@@ -0,0 +1,163 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package k8s_discovery_hdl
+
+import (
+	"context"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/components/k8s_clt"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util"
+	"github.com/SENERGY-Platform/go-service-base/struct-logger"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testResponses = map[string][]string{
+	"/api/v1/namespaces/test/services": {
+		`{"metadata": {"continue": "1"}, "items": [
+  {"metadata": {"name": "srv-a", "namespace": "test", "annotations": {"api-docs/paths": "/a, /b", "api-docs/port": "http", "api-docs/methods": "get,post", "api-docs/path": "/openapi.json"}},
+   "spec": {"ports": [{"name": "metrics", "port": 9090}, {"name": "http", "port": 8080}]}},
+  {"metadata": {"name": "srv-b", "namespace": "test"},
+   "spec": {"ports": [{"name": "https", "port": 443}]}}
+]}`,
+		`{"metadata": {}, "items": [
+  {"metadata": {"name": "srv-c", "namespace": "test", "annotations": {"api-docs/paths": "/c", "api-docs/port": "8080"}},
+   "spec": {"ports": [{"name": "http", "port": 80}]}},
+  {"metadata": {"name": "srv-d", "namespace": "test", "annotations": {"api-docs/paths": "/d", "api-docs/strip-path": "false", "api-docs/disabled": "true"}},
+   "spec": {"ports": [{"name": "http", "port": 80}]}}
+]}`,
+	},
+	"/apis/networking.k8s.io/v1/namespaces/test/ingresses": {
+		`{"metadata": {}, "items": [
+  {"metadata": {"name": "ing-a", "namespace": "test", "annotations": {"api-docs/enabled": "true", "api-docs/timeout": "5s"}},
+   "spec": {"rules": [
+     {"host": "api.test", "http": {"paths": [
+       {"path": "/b-api", "pathType": "Prefix", "backend": {"service": {"name": "srv-b", "port": {"number": 443}}}},
+       {"path": "/x", "pathType": "Prefix", "backend": {"service": {"name": "srv-x", "port": {"number": 80}}}}
+     ]}}
+   ]}},
+  {"metadata": {"name": "ing-b", "namespace": "test"},
+   "spec": {"rules": [
+     {"http": {"paths": [
+       {"path": "/c-api", "pathType": "Prefix", "backend": {"service": {"name": "srv-c", "port": {"name": "http"}}}}
+     ]}}
+   ]}}
+]}`,
+	},
+}
+
+func TestHandler_GetServices(t *testing.T) {
+	util.InitLogger(struct_logger.Config{}, os.Stderr, "", "")
+	InitLogger()
+	tokenPath := path.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenPath, []byte("test-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		pages, ok := testResponses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		i := 0
+		if r.URL.Query().Get("continue") == "1" {
+			i = 1
+		}
+		_, _ = w.Write([]byte(pages[i]))
+	}))
+	defer server.Close()
+	hdl := New(k8s_clt.New(server.Client(), server.URL, tokenPath, 2), []string{"test"}, time.Second, nil)
+	a := map[string]models.Service{
+		"srv-a.test.svc8080": {
+			ID:       "srv-a.test.svc8080",
+			Host:     "srv-a.test.svc",
+			Port:     8080,
+			Protocol: "http",
+			ExtPaths: []string{"/a", "/b"},
+			Routes: map[string]models.Route{
+				"/a": {Methods: []string{"GET", "POST"}, StripPath: true},
+				"/b": {Methods: []string{"GET", "POST"}, StripPath: true},
+			},
+			DocOptions: models.DocOptions{Path: "/openapi.json"},
+		},
+		"srv-b.test.svc443": {
+			ID:       "srv-b.test.svc443",
+			Host:     "srv-b.test.svc",
+			Port:     443,
+			Protocol: "https",
+			ExtPaths: []string{"/b-api"},
+			Routes: map[string]models.Route{
+				"/b-api": {Hosts: []string{"api.test"}},
+			},
+			DocOptions: models.DocOptions{Timeout: time.Second * 5},
+		},
+		"srv-d.test.svc80": {
+			ID:       "srv-d.test.svc80",
+			Host:     "srv-d.test.svc",
+			Port:     80,
+			Protocol: "http",
+			ExtPaths: []string{"/d"},
+			Routes: map[string]models.Route{
+				"/d": {},
+			},
+			DocOptions: models.DocOptions{Disabled: true},
+		},
+	}
+	b, err := hdl.GetServices(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("expected: %v, got: %v", a, b)
+	}
+	t.Run("error", func(t *testing.T) {
+		hdl = New(k8s_clt.New(server.Client(), server.URL, "", 2), []string{"test"}, time.Second, nil)
+		if _, err = hdl.GetServices(context.Background()); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func Test_getServicePort(t *testing.T) {
+	var kService k8s_clt.Service
+	if _, err := getServicePort(kService, ""); err == nil {
+		t.Error("expected error")
+	}
+	kService.Spec.Ports = []k8s_clt.ServicePort{{Name: "metrics", Port: 9090}, {Name: "http", Port: 8080}}
+	for ref, a := range map[string]int{"": 9090, "http": 8080, "9090": 9090} {
+		b, err := getServicePort(kService, ref)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if b.Port != a {
+			t.Errorf("expected %d, got %d", a, b.Port)
+		}
+	}
+	if _, err := getServicePort(kService, "https"); err == nil {
+		t.Error("expected error")
+	}
+}
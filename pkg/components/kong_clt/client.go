@@ -18,9 +18,12 @@ package kong_clt
 
 import (
 	"context"
+	"fmt"
 	base_client "github.com/SENERGY-Platform/go-base-http-client"
 	"net/http"
 	"net/url"
+	"slices"
+	"strconv"
 )
 
 type ClientItf interface {
@@ -28,56 +31,129 @@ type ClientItf interface {
 	GetServices(ctx context.Context) ([]Service, error)
 }
 
+const AllWorkspaces = "all"
+
 type Client struct {
 	baseClient *base_client.Client
 	baseUrl    string
 	username   string
 	password   string
+	pageSize   int
+	workspaces []string
 }
 
-func New(httpClient base_client.HTTPClient, baseUrl, username, password string) *Client {
+func New(httpClient base_client.HTTPClient, baseUrl, username, password string, pageSize int, workspaces []string) *Client {
 	return &Client{
 		baseClient: base_client.New(httpClient, customError, ""),
 		baseUrl:    baseUrl,
 		username:   username,
 		password:   password,
+		pageSize:   pageSize,
+		workspaces: workspaces,
 	}
 }
 
 func (c *Client) GetRoutes(ctx context.Context) ([]Route, error) {
-	u, err := url.JoinPath(c.baseUrl, "routes")
+	workspaces, err := c.getWorkspaces(ctx)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	var routes []Route
+	for _, ws := range workspaces {
+		items, err := getAll[Route](ctx, c, ws, "routes")
+		if err != nil {
+			return nil, err
+		}
+		for i := range items {
+			items[i].Workspace = ws
+		}
+		routes = append(routes, items...)
+	}
+	return routes, nil
+}
+
+func (c *Client) GetServices(ctx context.Context) ([]Service, error) {
+	workspaces, err := c.getWorkspaces(ctx)
 	if err != nil {
 		return nil, err
 	}
-	c.setBasicAuth(req)
-	var resp routes
-	err = c.baseClient.ExecRequestJSON(req, &resp)
+	var services []Service
+	for _, ws := range workspaces {
+		items, err := getAll[Service](ctx, c, ws, "services")
+		if err != nil {
+			return nil, err
+		}
+		for i := range items {
+			items[i].Workspace = ws
+		}
+		services = append(services, items...)
+	}
+	return services, nil
+}
+
+func (c *Client) getWorkspaces(ctx context.Context) ([]string, error) {
+	if len(c.workspaces) == 0 {
+		return []string{""}, nil
+	}
+	if !slices.Contains(c.workspaces, AllWorkspaces) {
+		return c.workspaces, nil
+	}
+	items, err := getAll[workspace](ctx, c, "", "workspaces")
 	if err != nil {
 		return nil, err
 	}
-	return resp.Data, nil
+	var workspaces []string
+	for _, item := range items {
+		workspaces = append(workspaces, item.Name)
+	}
+	return workspaces, nil
 }
 
-func (c *Client) GetServices(ctx context.Context) ([]Service, error) {
-	u, err := url.JoinPath(c.baseUrl, "services")
+func getAll[T any](ctx context.Context, c *Client, ws, path string) ([]T, error) {
+	u, err := url.JoinPath(c.baseUrl, ws, path)
 	if err != nil {
 		return nil, err
 	}
+	var items []T
+	offsets := make(map[string]struct{})
+	var offset string
+	for {
+		resp, err := getPage[T](ctx, c, u, offset)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, resp.Data...)
+		if resp.Offset == "" {
+			break
+		}
+		if _, ok := offsets[resp.Offset]; ok {
+			return nil, fmt.Errorf("repeated offset '%s'", resp.Offset)
+		}
+		offsets[resp.Offset] = struct{}{}
+		offset = resp.Offset
+	}
+	return items, nil
+}
+
+func getPage[T any](ctx context.Context, c *Client, u, offset string) (page[T], error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
 	if err != nil {
-		return nil, err
+		return page[T]{}, err
+	}
+	query := req.URL.Query()
+	if c.pageSize > 0 {
+		query.Set("size", strconv.Itoa(c.pageSize))
+	}
+	if offset != "" {
+		query.Set("offset", offset)
 	}
+	req.URL.RawQuery = query.Encode()
 	c.setBasicAuth(req)
-	var resp services
-	err = c.baseClient.ExecRequestJSON(req, &resp)
-	if err != nil {
-		return nil, err
+	var resp page[T]
+	if err = c.baseClient.ExecRequestJSON(req, &resp); err != nil {
+		return page[T]{}, err
 	}
-	return resp.Data, nil
+	return resp, nil
 }
 
 func (c *Client) setBasicAuth(req *http.Request) {
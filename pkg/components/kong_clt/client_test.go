@@ -0,0 +1,156 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package kong_clt
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestClient_GetRoutes(t *testing.T) {
+	var items []Route
+	for i := 0; i < 5; i++ {
+		items = append(items, Route{ID: strconv.Itoa(i), Paths: []string{"/" + strconv.Itoa(i)}})
+	}
+	server := newKongServer(t, "/routes", items)
+	defer server.Close()
+	clt := New(server.Client(), server.URL, "user", "pw", 2, nil)
+	b, err := clt.GetRoutes(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(items, b) {
+		t.Errorf("expected: %v, got: %v", items, b)
+	}
+}
+
+func TestClient_GetServices(t *testing.T) {
+	var items []Service
+	for i := 0; i < 4; i++ {
+		items = append(items, Service{ID: strconv.Itoa(i), Host: "h" + strconv.Itoa(i), Port: i})
+	}
+	server := newKongServer(t, "/services", items)
+	defer server.Close()
+	clt := New(server.Client(), server.URL, "user", "pw", 2, nil)
+	b, err := clt.GetServices(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(items, b) {
+		t.Errorf("expected: %v, got: %v", items, b)
+	}
+	t.Run("error", func(t *testing.T) {
+		clt = New(server.Client(), server.URL+"/test", "", "", 2, nil)
+		if _, err = clt.GetServices(context.Background()); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestClient_GetServicesRepeatedOffset(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(page[Service]{Data: []Service{{ID: "1"}}, Offset: "x"})
+	}))
+	defer server.Close()
+	clt := New(server.Client(), server.URL, "", "", 0, nil)
+	if _, err := clt.GetServices(context.Background()); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestClient_Workspaces(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/workspaces":
+			_ = json.NewEncoder(w).Encode(page[workspace]{Data: []workspace{{ID: "1", Name: "ws-a"}, {ID: "2", Name: "ws-b"}}})
+		case "/ws-a/services":
+			_ = json.NewEncoder(w).Encode(page[Service]{Data: []Service{{ID: "s1"}}})
+		case "/ws-b/services":
+			_ = json.NewEncoder(w).Encode(page[Service]{Data: []Service{{ID: "s2"}, {ID: "s3"}}})
+		case "/ws-a/routes":
+			_ = json.NewEncoder(w).Encode(page[Route]{Data: []Route{{ID: "r1"}}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	t.Run("all", func(t *testing.T) {
+		clt := New(server.Client(), server.URL, "", "", 0, []string{AllWorkspaces})
+		b, err := clt.GetServices(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		a := []Service{{ID: "s1", Workspace: "ws-a"}, {ID: "s2", Workspace: "ws-b"}, {ID: "s3", Workspace: "ws-b"}}
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("expected: %v, got: %v", a, b)
+		}
+	})
+	t.Run("list", func(t *testing.T) {
+		clt := New(server.Client(), server.URL, "", "", 0, []string{"ws-a"})
+		b, err := clt.GetRoutes(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		a := []Route{{ID: "r1", Workspace: "ws-a"}}
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("expected: %v, got: %v", a, b)
+		}
+	})
+	t.Run("unknown", func(t *testing.T) {
+		clt := New(server.Client(), server.URL, "", "", 0, []string{"ws-c"})
+		if _, err := clt.GetRoutes(context.Background()); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func newKongServer[T any](t *testing.T, path string, items []T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if user, pw, ok := r.BasicAuth(); !ok || user != "user" || pw != "pw" {
+			t.Error("missing basic auth")
+		}
+		size, err := strconv.Atoi(r.URL.Query().Get("size"))
+		if err != nil {
+			t.Error(err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var offset int
+		if val := r.URL.Query().Get("offset"); val != "" {
+			if offset, err = strconv.Atoi(val); err != nil {
+				t.Error(err)
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+		end := min(offset+size, len(items))
+		resp := page[T]{Data: items[offset:end]}
+		if end < len(items) {
+			resp.Offset = strconv.Itoa(end)
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+}
@@ -18,11 +18,11 @@ package kong_clt
 
 type Route struct {
 	//Tags                    interface{}  `json:"tags"`
-	//StripPath               bool         `json:"strip_path"`
+	StripPath *bool `json:"strip_path"`
 	//RegexPriority           int          `json:"regex_priority"`
-	//Hosts                   interface{}  `json:"hosts"`
-	Name string `json:"name"`
-	ID   string `json:"id"`
+	Hosts []string `json:"hosts"`
+	Name  string   `json:"name"`
+	ID    string   `json:"id"`
 	//PreserveHost            bool         `json:"preserve_host"`
 	//CreatedAt               int          `json:"created_at"`
 	//Sources                 interface{}  `json:"sources"`
@@ -30,7 +30,7 @@ type Route struct {
 	Paths []string `json:"paths"`
 	//PathHandling            string       `json:"path_handling"`
 	//Protocols               []string     `json:"protocols"`
-	//Methods                 interface{}  `json:"methods"`
+	Methods []string `json:"methods"`
 	//RequestBuffering        bool         `json:"request_buffering"`
 	//ResponseBuffering       bool         `json:"response_buffering"`
 	//Headers                 interface{}  `json:"headers"`
@@ -40,13 +40,14 @@ type Route struct {
 	Service struct {
 		ID string `json:"id"`
 	} `json:"service"`
+	Workspace string `json:"-"`
 }
 
 type Service struct {
-	//Tags              interface{} `json:"tags"`
+	Tags []string `json:"tags"`
 	//CaCertificates    interface{} `json:"ca_certificates"`
 	//ClientCertificate interface{} `json:"client_certificate"`
-	//Name           string      `json:"name"`
+	Name string `json:"name"`
 	//Path           interface{} `json:"path"`
 	//ConnectTimeout int         `json:"connect_timeout"`
 	//WriteTimeout   int         `json:"write_timeout"`
@@ -60,13 +61,16 @@ type Service struct {
 	ID string `json:"id"`
 	//CreatedAt      int         `json:"created_at"`
 	//ReadTimeout    int         `json:"read_timeout"`
-	Port int `json:"port"`
+	Port      int    `json:"port"`
+	Workspace string `json:"-"`
 }
 
-type routes struct {
-	Data []Route `json:"data"`
+type workspace struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
 }
 
-type services struct {
-	Data []Service `json:"data"`
+type page[T any] struct {
+	Data   []T    `json:"data"`
+	Offset string `json:"offset"`
 }
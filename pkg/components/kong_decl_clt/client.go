@@ -0,0 +1,203 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package kong_decl_clt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/components/kong_clt"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var defaultPorts = map[string]int{
+	"http":  80,
+	"https": 443,
+	"grpc":  80,
+	"grpcs": 443,
+}
+
+type Client struct {
+	path     string
+	modTime  time.Time
+	size     int64
+	routes   []kong_clt.Route
+	services []kong_clt.Service
+	mu       sync.Mutex
+}
+
+func New(path string) *Client {
+	return &Client{path: path}
+}
+
+func (c *Client) GetRoutes(ctx context.Context) ([]kong_clt.Route, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.load(ctx); err != nil {
+		return nil, err
+	}
+	return c.routes, nil
+}
+
+func (c *Client) GetServices(ctx context.Context) ([]kong_clt.Service, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.load(ctx); err != nil {
+		return nil, err
+	}
+	return c.services, nil
+}
+
+func (c *Client) load(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return err
+	}
+	if info.ModTime().Equal(c.modTime) && info.Size() == c.size && c.services != nil {
+		return nil
+	}
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return err
+	}
+	routes, services, err := parseConfig(data)
+	if err != nil {
+		return err
+	}
+	c.routes = routes
+	c.services = services
+	c.modTime = info.ModTime()
+	c.size = info.Size()
+	return nil
+}
+
+func parseConfig(data []byte) ([]kong_clt.Route, []kong_clt.Service, error) {
+	if util.DetectDocFormat("", data) == models.DocFormatYAML {
+		var err error
+		if data, err = util.YAMLToJSON(data); err != nil {
+			return nil, nil, err
+		}
+	}
+	var cfg config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, nil, err
+	}
+	if cfg.FormatVersion == "" {
+		return nil, nil, errors.New("missing format version")
+	}
+	services := make([]kong_clt.Service, 0, len(cfg.Services))
+	srvIDs := make(map[string]string)
+	var routes []kong_clt.Route
+	for _, s := range cfg.Services {
+		kService, err := newService(s)
+		if err != nil {
+			return nil, nil, err
+		}
+		kService.Workspace = cfg.Workspace
+		services = append(services, kService)
+		if s.ID != "" {
+			srvIDs[s.ID] = kService.ID
+		}
+		if s.Name != "" {
+			srvIDs[s.Name] = kService.ID
+		}
+		for _, r := range s.Routes {
+			routes = append(routes, newRoute(r, kService.ID, cfg.Workspace))
+		}
+	}
+	for _, r := range cfg.Routes {
+		ref := r.Service.ID
+		if ref == "" {
+			ref = r.Service.Name
+		}
+		srvID, ok := srvIDs[ref]
+		if !ok {
+			return nil, nil, fmt.Errorf("route '%s': service '%s' not found", r.Name, ref)
+		}
+		routes = append(routes, newRoute(r, srvID, cfg.Workspace))
+	}
+	return routes, services, nil
+}
+
+func newService(s service) (kong_clt.Service, error) {
+	kService := kong_clt.Service{
+		ID:       s.ID,
+		Name:     s.Name,
+		Host:     s.Host,
+		Port:     s.Port,
+		Protocol: s.Protocol,
+		Tags:     s.Tags,
+	}
+	if kService.ID == "" {
+		kService.ID = s.Name
+	}
+	if kService.ID == "" {
+		return kong_clt.Service{}, errors.New("service without id or name")
+	}
+	if s.URL != "" {
+		u, err := url.Parse(s.URL)
+		if err != nil {
+			return kong_clt.Service{}, fmt.Errorf("service '%s': %w", kService.ID, err)
+		}
+		kService.Protocol = u.Scheme
+		kService.Host = u.Hostname()
+		if p := u.Port(); p != "" {
+			if kService.Port, err = strconv.Atoi(p); err != nil {
+				return kong_clt.Service{}, fmt.Errorf("service '%s': %w", kService.ID, err)
+			}
+		} else {
+			kService.Port = 0
+		}
+	}
+	if kService.Host == "" {
+		return kong_clt.Service{}, fmt.Errorf("service '%s': missing host", kService.ID)
+	}
+	if kService.Protocol == "" {
+		kService.Protocol = "http"
+	}
+	if kService.Port == 0 {
+		kService.Port = defaultPorts[kService.Protocol]
+	}
+	return kService, nil
+}
+
+func newRoute(r route, srvID, workspace string) kong_clt.Route {
+	kRoute := kong_clt.Route{
+		Name:      r.Name,
+		ID:        r.ID,
+		Paths:     r.Paths,
+		Methods:   r.Methods,
+		Hosts:     r.Hosts,
+		StripPath: r.StripPath,
+		Workspace: workspace,
+	}
+	if kRoute.ID == "" {
+		kRoute.ID = r.Name
+	}
+	kRoute.Service.ID = srvID
+	return kRoute
+}
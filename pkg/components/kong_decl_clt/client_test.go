@@ -0,0 +1,126 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package kong_decl_clt
+
+import (
+	"context"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/components/kong_clt"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const testConfig = `_format_version: "3.0"
+services:
+  - name: srv-a
+    url: http://api.srv-a:8000/base
+    tags: [api-docs:path=/openapi.json]
+    routes:
+      - name: route-a
+        paths: [/a]
+        methods: [GET]
+        strip_path: false
+  - name: srv-b
+    id: s2
+    host: api.srv-b
+    protocol: https
+routes:
+  - name: route-b
+    paths: [/b, /c]
+    hosts: [api.test]
+    service: srv-b
+  - name: route-c
+    paths: [/d]
+    service:
+      id: s2
+`
+
+func TestClient(t *testing.T) {
+	p := path.Join(t.TempDir(), "kong.yml")
+	if err := os.WriteFile(p, []byte(testConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stripPath := false
+	aRoutes := []kong_clt.Route{
+		{Name: "route-a", ID: "route-a", Paths: []string{"/a"}, Methods: []string{"GET"}, StripPath: &stripPath},
+		{Name: "route-b", ID: "route-b", Paths: []string{"/b", "/c"}, Hosts: []string{"api.test"}},
+		{Name: "route-c", ID: "route-c", Paths: []string{"/d"}},
+	}
+	aRoutes[0].Service.ID = "srv-a"
+	aRoutes[1].Service.ID = "s2"
+	aRoutes[2].Service.ID = "s2"
+	aServices := []kong_clt.Service{
+		{ID: "srv-a", Name: "srv-a", Host: "api.srv-a", Port: 8000, Protocol: "http", Tags: []string{"api-docs:path=/openapi.json"}},
+		{ID: "s2", Name: "srv-b", Host: "api.srv-b", Port: 443, Protocol: "https"},
+	}
+	clt := New(p)
+	bRoutes, err := clt.GetRoutes(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(aRoutes, bRoutes) {
+		t.Errorf("expected: %v, got: %v", aRoutes, bRoutes)
+	}
+	bServices, err := clt.GetServices(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(aServices, bServices) {
+		t.Errorf("expected: %v, got: %v", aServices, bServices)
+	}
+	t.Run("reload", func(t *testing.T) {
+		if err = os.WriteFile(p, []byte(`{"_format_version": "3.0", "services": [{"name": "srv-c", "host": "api.srv-c"}]}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		modTime := time.Now().Add(time.Second)
+		if err = os.Chtimes(p, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+		bServices, err = clt.GetServices(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		a := []kong_clt.Service{{ID: "srv-c", Name: "srv-c", Host: "api.srv-c", Port: 80, Protocol: "http"}}
+		if !reflect.DeepEqual(a, bServices) {
+			t.Errorf("expected: %v, got: %v", a, bServices)
+		}
+		bRoutes, err = clt.GetRoutes(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(bRoutes) != 0 {
+			t.Errorf("expected 0 routes, got %d", len(bRoutes))
+		}
+	})
+	t.Run("error", func(t *testing.T) {
+		for name, data := range map[string]string{
+			"format version":  `services: []`,
+			"missing service": "_format_version: \"3.0\"\nroutes:\n  - name: r\n    service: x\n",
+			"missing host":    "_format_version: \"3.0\"\nservices:\n  - name: s\n",
+			"missing name":    "_format_version: \"3.0\"\nservices:\n  - host: h\n",
+		} {
+			if _, _, err = parseConfig([]byte(data)); err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+		}
+		if _, err = New(path.Join(t.TempDir(), "missing.yml")).GetServices(context.Background()); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
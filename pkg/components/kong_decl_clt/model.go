@@ -0,0 +1,69 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package kong_decl_clt
+
+import (
+	"encoding/json"
+)
+
+type config struct {
+	FormatVersion string    `json:"_format_version"`
+	Workspace     string    `json:"_workspace"`
+	Services      []service `json:"services"`
+	Routes        []route   `json:"routes"`
+}
+
+type service struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	URL      string   `json:"url"`
+	Host     string   `json:"host"`
+	Port     int      `json:"port"`
+	Protocol string   `json:"protocol"`
+	Tags     []string `json:"tags"`
+	Routes   []route  `json:"routes"`
+}
+
+type route struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Paths     []string   `json:"paths"`
+	Methods   []string   `json:"methods"`
+	Hosts     []string   `json:"hosts"`
+	StripPath *bool      `json:"strip_path"`
+	Service   serviceRef `json:"service"`
+}
+
+type serviceRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func (r *serviceRef) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err == nil {
+		r.Name = name
+		return nil
+	}
+	type ref serviceRef
+	var tmp ref
+	if err := json.Unmarshal(b, &tmp); err != nil {
+		return err
+	}
+	*r = serviceRef(tmp)
+	return nil
+}
@@ -0,0 +1,172 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report_hdl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	lib_models "github.com/SENERGY-Platform/api-docs-provider/lib/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util/slog_attr"
+	"github.com/SENERGY-Platform/go-service-base/struct-logger/attributes"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const fileExt = ".json"
+
+type Handler struct {
+	dirPath string
+	maxRuns int
+	mu      sync.RWMutex
+	runs    map[string]lib_models.ProcurementRun
+	logger  *slog.Logger
+}
+
+func New(dirPath string, maxRuns int) *Handler {
+	return &Handler{
+		dirPath: dirPath,
+		maxRuns: maxRuns,
+		runs:    make(map[string]lib_models.ProcurementRun),
+		logger:  util.Logger.With(slog_attr.ComponentKey, "report-hdl"),
+	}
+}
+
+func (h *Handler) Init(ctx context.Context) error {
+	if err := os.MkdirAll(h.dirPath, 0770); err != nil {
+		return err
+	}
+	dirEntries, err := fs.ReadDir(os.DirFS(h.dirPath), ".")
+	if err != nil {
+		return err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, dirEntry := range dirEntries {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if dirEntry.IsDir() || !strings.HasSuffix(dirEntry.Name(), fileExt) {
+			continue
+		}
+		run, err := readRun(path.Join(h.dirPath, dirEntry.Name()))
+		if err != nil {
+			h.logger.Error("reading report failed", slog_attr.NameKey, dirEntry.Name(), attributes.ErrorKey, err)
+			continue
+		}
+		h.runs[run.ID] = run
+	}
+	h.prune()
+	return nil
+}
+
+func (h *Handler) Put(_ context.Context, run lib_models.ProcurementRun) error {
+	if run.ID == "" {
+		return lib_models.NewInvalidInputError(errors.New("missing id"))
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err := writeRun(h.dirPath, run); err != nil {
+		return lib_models.NewInternalError(err)
+	}
+	h.runs[run.ID] = run
+	h.prune()
+	return nil
+}
+
+func (h *Handler) Get(_ context.Context, id string) (lib_models.ProcurementRun, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	run, ok := h.runs[id]
+	if !ok {
+		return lib_models.ProcurementRun{}, lib_models.NewNotFoundError(errors.New("not found"))
+	}
+	return run, nil
+}
+
+func (h *Handler) List(_ context.Context) ([]lib_models.ProcurementRunInfo, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	runs := h.sortedRuns()
+	infos := make([]lib_models.ProcurementRunInfo, 0, len(runs))
+	for _, run := range runs {
+		infos = append(infos, run.ProcurementRunInfo)
+	}
+	return infos, nil
+}
+
+func (h *Handler) sortedRuns() []lib_models.ProcurementRun {
+	runs := make([]lib_models.ProcurementRun, 0, len(h.runs))
+	for _, run := range h.runs {
+		runs = append(runs, run)
+	}
+	slices.SortFunc(runs, func(a, b lib_models.ProcurementRun) int {
+		if c := b.Start.Compare(a.Start); c != 0 {
+			return c
+		}
+		return strings.Compare(b.ID, a.ID)
+	})
+	return runs
+}
+
+func (h *Handler) prune() {
+	if h.maxRuns <= 0 || len(h.runs) <= h.maxRuns {
+		return
+	}
+	for _, run := range h.sortedRuns()[h.maxRuns:] {
+		if err := os.Remove(path.Join(h.dirPath, run.ID+fileExt)); err != nil && !os.IsNotExist(err) {
+			h.logger.Error("removing report failed", slog_attr.IDKey, run.ID, attributes.ErrorKey, err)
+			continue
+		}
+		delete(h.runs, run.ID)
+	}
+}
+
+func readRun(p string) (lib_models.ProcurementRun, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return lib_models.ProcurementRun{}, err
+	}
+	defer f.Close()
+	var run lib_models.ProcurementRun
+	if err = json.NewDecoder(f).Decode(&run); err != nil {
+		return lib_models.ProcurementRun{}, err
+	}
+	return run, nil
+}
+
+func writeRun(dirPath string, run lib_models.ProcurementRun) error {
+	b, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+	tmpPath := path.Join(dirPath, run.ID+fileExt+".tmp")
+	if err = os.WriteFile(tmpPath, b, 0660); err != nil {
+		return err
+	}
+	if err = os.Rename(tmpPath, path.Join(dirPath, run.ID+fileExt)); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report_hdl
+
+import (
+	"context"
+	"errors"
+	lib_models "github.com/SENERGY-Platform/api-docs-provider/lib/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util"
+	"github.com/SENERGY-Platform/go-service-base/struct-logger"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHandler(t *testing.T) {
+	util.InitLogger(struct_logger.Config{}, os.Stderr, "", "")
+	tmpDir := t.TempDir()
+	hdl := New(tmpDir, 2)
+	if err := hdl.Init(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var runs []lib_models.ProcurementRun
+	for i, id := range []string{"a", "b", "c"} {
+		runs = append(runs, lib_models.ProcurementRun{
+			ProcurementRunInfo: lib_models.ProcurementRunInfo{
+				ID:         id,
+				Type:       lib_models.ProcurementRunTypeFull,
+				Start:      start.Add(time.Duration(i) * time.Minute),
+				End:        start.Add(time.Duration(i)*time.Minute + time.Second),
+				Discovered: 1,
+				Outcomes:   map[string]int{lib_models.ProcurementOutcomeFetched: 1},
+			},
+			Services: []lib_models.ProcurementServiceResult{
+				{ID: "s1", Host: "h", Port: 80, Outcome: lib_models.ProcurementOutcomeFetched},
+			},
+		})
+	}
+	t.Run("put", func(t *testing.T) {
+		for _, run := range runs {
+			if err := hdl.Put(context.Background(), run); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+	t.Run("list", func(t *testing.T) {
+		infos, err := hdl.List(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		a := []lib_models.ProcurementRunInfo{runs[2].ProcurementRunInfo, runs[1].ProcurementRunInfo}
+		if !reflect.DeepEqual(a, infos) {
+			t.Errorf("expected %v, got %v", a, infos)
+		}
+	})
+	t.Run("get", func(t *testing.T) {
+		run, err := hdl.Get(context.Background(), "c")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(runs[2], run) {
+			t.Errorf("expected %v, got %v", runs[2], run)
+		}
+	})
+	t.Run("pruned", func(t *testing.T) {
+		_, err := hdl.Get(context.Background(), "a")
+		var nfe *lib_models.NotFoundError
+		if !errors.As(err, &nfe) {
+			t.Errorf("expected not found error, got %v", err)
+		}
+		if _, err = os.Stat(tmpDir + "/a.json"); !os.IsNotExist(err) {
+			t.Error("expected file to be removed")
+		}
+	})
+	t.Run("init", func(t *testing.T) {
+		hdl2 := New(tmpDir, 2)
+		if err := hdl2.Init(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		run, err := hdl2.Get(context.Background(), "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(runs[1], run) {
+			t.Errorf("expected %v, got %v", runs[1], run)
+		}
+	})
+	t.Run("missing id", func(t *testing.T) {
+		if err := hdl.Put(context.Background(), lib_models.ProcurementRun{}); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
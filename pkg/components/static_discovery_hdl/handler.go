@@ -0,0 +1,104 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package static_discovery_hdl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	lib_models "github.com/SENERGY-Platform/api-docs-provider/lib/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/components/discovery_hdl"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util/slog_attr"
+	"github.com/SENERGY-Platform/go-service-base/struct-logger/attributes"
+	"os"
+	"strings"
+)
+
+type Handler struct {
+	path  string
+	rules []models.DiscoveryRule
+}
+
+func New(path string, rules []models.DiscoveryRule) *Handler {
+	return &Handler{path: path, rules: rules}
+}
+
+func (h *Handler) GetServices(_ context.Context) (map[string]models.Service, error) {
+	data, err := os.ReadFile(h.path)
+	if err != nil {
+		return nil, lib_models.NewInternalError(err)
+	}
+	if util.DetectDocFormat("", data) == models.DocFormatYAML {
+		if data, err = util.YAMLToJSON(data); err != nil {
+			return nil, lib_models.NewInternalError(err)
+		}
+	}
+	var items []service
+	if err = json.Unmarshal(data, &items); err != nil {
+		return nil, lib_models.NewInternalError(err)
+	}
+	services := make(map[string]models.Service)
+	for _, item := range items {
+		if item.Host == "" {
+			return nil, lib_models.NewInternalError(errors.New("missing host"))
+		}
+		id := fmt.Sprintf("%s%d", item.Host, item.Port)
+		if _, ok := services[id]; ok {
+			return nil, lib_models.NewInternalError(fmt.Errorf("duplicate service '%s'", id))
+		}
+		service := models.Service{
+			ID:       id,
+			Host:     item.Host,
+			Port:     item.Port,
+			Protocol: item.Protocol,
+			Routes:   make(map[string]models.Route),
+		}
+		if service.Protocol == "" {
+			service.Protocol = "http"
+		}
+		for _, option := range item.Options {
+			if err = util.ParseDocOption(&service.DocOptions, option); err != nil {
+				logger.Warn("parsing doc option failed", slog_attr.HostKey, item.Host, slog_attr.PortKey, item.Port, attributes.ErrorKey, fmt.Errorf("option '%s': %w", option, err))
+			}
+		}
+		for _, r := range item.Routes {
+			mRoute := models.Route{
+				Hosts:     r.Hosts,
+				StripPath: r.StripPath == nil || *r.StripPath,
+			}
+			for _, method := range r.Methods {
+				mRoute.Methods = append(mRoute.Methods, strings.ToUpper(method))
+			}
+			for _, extPath := range r.Paths {
+				if _, ok := service.Routes[extPath]; ok {
+					continue
+				}
+				service.ExtPaths = append(service.ExtPaths, extPath)
+				service.Routes[extPath] = mRoute
+			}
+		}
+		services[id] = service
+	}
+	services = discovery_hdl.FilterServices(h.rules, services)
+	for _, service := range services {
+		logger.Debug("found service", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, slog_attr.ExternalPathsKey, service.ExtPaths, slog_attr.DocOptionsKey, service.DocOptions)
+	}
+	return services, nil
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package static_discovery_hdl
+
+import (
+	"context"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util"
+	"github.com/SENERGY-Platform/go-service-base/struct-logger"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHandler_GetServices(t *testing.T) {
+	util.InitLogger(struct_logger.Config{}, os.Stderr, "", "")
+	InitLogger()
+	a := map[string]models.Service{
+		"api.srv-a8000": {
+			ID:       "api.srv-a8000",
+			Host:     "api.srv-a",
+			Port:     8000,
+			Protocol: "http",
+			ExtPaths: []string{"/a", "/b"},
+			Routes: map[string]models.Route{
+				"/a": {Methods: []string{"GET"}, StripPath: true},
+				"/b": {Hosts: []string{"api.test"}},
+			},
+			DocOptions: models.DocOptions{
+				Path:    "/openapi.json",
+				Timeout: time.Second * 10,
+			},
+		},
+		"api.srv-b80": {
+			ID:       "api.srv-b80",
+			Host:     "api.srv-b",
+			Port:     80,
+			Protocol: "https",
+			Routes:   map[string]models.Route{},
+		},
+	}
+	tmpDir := t.TempDir()
+	t.Run("json", func(t *testing.T) {
+		p := path.Join(tmpDir, "services.json")
+		err := os.WriteFile(p, []byte(`[
+  {"host": "api.srv-a", "port": 8000, "options": ["path=/openapi.json", "timeout=10s", "protocol=ftp"], "routes": [
+    {"paths": ["/a"], "methods": ["get"]},
+    {"paths": ["/b", "/a"], "hosts": ["api.test"], "strip_path": false}
+  ]},
+  {"host": "api.srv-b", "port": 80, "protocol": "https"}
+]`), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := New(p, nil).GetServices(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("expected: %v, got: %v", a, b)
+		}
+	})
+	t.Run("yaml", func(t *testing.T) {
+		p := path.Join(tmpDir, "services.yml")
+		err := os.WriteFile(p, []byte(`- host: api.srv-a
+  port: 8000
+  options: [path=/openapi.json, timeout=10s]
+  routes:
+    - paths: [/a]
+      methods: [GET]
+    - paths: [/b]
+      hosts: [api.test]
+      strip_path: false
+- host: api.srv-b
+  port: 80
+  protocol: https
+`), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := New(p, nil).GetServices(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("expected: %v, got: %v", a, b)
+		}
+	})
+	t.Run("rules", func(t *testing.T) {
+		p := path.Join(tmpDir, "services.json")
+		rules := []models.DiscoveryRule{
+			{Action: models.DiscoveryRuleDeny, Host: "api.srv-b"},
+			{Action: models.DiscoveryRuleDeny, Path: "/b"},
+		}
+		b, err := New(p, rules).GetServices(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := map[string]models.Service{
+			"api.srv-a8000": {
+				ID:          "api.srv-a8000",
+				Host:        "api.srv-a",
+				Port:        8000,
+				Protocol:    "http",
+				ExtPaths:    []string{"/a"},
+				DeniedPaths: []string{"/b"},
+				Routes: map[string]models.Route{
+					"/a": {Methods: []string{"GET"}, StripPath: true},
+				},
+				DocOptions: models.DocOptions{
+					Path:    "/openapi.json",
+					Timeout: time.Second * 10,
+				},
+			},
+			"api.srv-b80": {
+				ID:       "api.srv-b80",
+				Host:     "api.srv-b",
+				Port:     80,
+				Protocol: "https",
+				Denied:   true,
+				Routes:   map[string]models.Route{},
+			},
+		}
+		if !reflect.DeepEqual(c, b) {
+			t.Errorf("expected: %v, got: %v", c, b)
+		}
+	})
+	t.Run("error", func(t *testing.T) {
+		for name, data := range map[string]string{
+			"missing host": `[{"port": 80}]`,
+			"duplicate":    `[{"host": "a", "port": 80}, {"host": "a", "port": 80}]`,
+			"invalid":      `{"host": "a"}`,
+		} {
+			p := path.Join(tmpDir, "invalid.json")
+			if err := os.WriteFile(p, []byte(data), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := New(p, nil).GetServices(context.Background()); err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+		}
+		if _, err := New(path.Join(tmpDir, "missing.json"), nil).GetServices(context.Background()); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
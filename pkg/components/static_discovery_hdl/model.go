@@ -0,0 +1,32 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package static_discovery_hdl
+
+type service struct {
+	Host     string   `json:"host"`
+	Port     int      `json:"port"`
+	Protocol string   `json:"protocol"`
+	Routes   []route  `json:"routes"`
+	Options  []string `json:"options"`
+}
+
+type route struct {
+	Paths     []string `json:"paths"`
+	Methods   []string `json:"methods"`
+	Hosts     []string `json:"hosts"`
+	StripPath *bool    `json:"strip_path"`
+}
@@ -19,6 +19,8 @@ package storage_hdl
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	lib_models "github.com/SENERGY-Platform/api-docs-provider/lib/models"
@@ -32,7 +34,9 @@ import (
 	"log/slog"
 	"os"
 	"path"
+	"slices"
 	"sync"
+	"time"
 )
 
 const (
@@ -41,17 +45,21 @@ const (
 )
 
 type Handler struct {
-	dirPath string
-	mu      sync.RWMutex
-	items   map[string]storageItem
-	logger  *slog.Logger
+	dirPath      string
+	maxRevisions int
+	mu           sync.RWMutex
+	items        map[string]storageItem
+	revisions    map[string][]storageItem
+	logger       *slog.Logger
 }
 
-func New(dirPath, name string) *Handler {
+func New(dirPath, name string, maxRevisions int) *Handler {
 	return &Handler{
-		dirPath: dirPath,
-		items:   make(map[string]storageItem),
-		logger:  util.Logger.With(slog_attr.ComponentKey, name+"-storage-hdl"),
+		dirPath:      dirPath,
+		maxRevisions: maxRevisions,
+		items:        make(map[string]storageItem),
+		revisions:    make(map[string][]storageItem),
+		logger:       util.Logger.With(slog_attr.ComponentKey, name+"-storage-hdl"),
 	}
 }
 
@@ -70,16 +78,18 @@ func (h *Handler) Init(ctx context.Context) error {
 			return ctx.Err()
 		}
 		if dirEntry.IsDir() {
-			se := storageItem{dirName: dirEntry.Name()}
-			data, err := readData(path.Join(h.dirPath, se.dirName, dataFileName))
+			se, err := readData(path.Join(h.dirPath, dirEntry.Name(), dataFileName))
 			if err != nil {
-				h.logger.Error("reading storage item failed", slog_attr.DirNameKey, se.dirName, attributes.ErrorKey, err)
+				h.logger.Error("reading storage item failed", slog_attr.DirNameKey, dirEntry.Name(), attributes.ErrorKey, err)
 			}
-			se.StorageData = data
+			se.dirName = dirEntry.Name()
 			h.logger.Debug("loaded storage item", slog_attr.IDKey, se.ID, slog_attr.DirNameKey, se.dirName)
-			h.items[se.ID] = se
+			h.addItem(se)
 		}
 	}
+	for id := range h.revisions {
+		h.pruneRevisions(ctx, id)
+	}
 	return nil
 }
 
@@ -88,7 +98,14 @@ func (h *Handler) List(_ context.Context) ([]models.StorageData, error) {
 	defer h.mu.RUnlock()
 	var items []models.StorageData
 	for _, item := range h.items {
-		items = append(items, item.StorageData)
+		sd := item.StorageData
+		if item.PinnedRevision != "" {
+			if revision, ok := h.getRevision(item.ID, item.PinnedRevision); ok {
+				sd.Args = revision.Args
+				sd.LatestArgs = item.Args
+			}
+		}
+		items = append(items, sd)
 	}
 	return items, nil
 }
@@ -96,6 +113,10 @@ func (h *Handler) List(_ context.Context) ([]models.StorageData, error) {
 func (h *Handler) Write(ctx context.Context, id string, args [][2]string, data []byte) error {
 	h.mu.Lock()
 	defer h.mu.Unlock()
+	return h.write(ctx, id, args, data, h.items[id].PinnedRevision)
+}
+
+func (h *Handler) write(ctx context.Context, id string, args [][2]string, data []byte, pinnedRevision string) error {
 	var err error
 	newDirName, err := genDirName()
 	if err != nil {
@@ -113,19 +134,18 @@ func (h *Handler) Write(ctx context.Context, id string, args [][2]string, data [
 			}
 		}
 	}()
-	item, ok := h.items[id]
-	if !ok {
-		item.ID = id
-	}
-	oldDirName := item.dirName
-	item.dirName = newDirName
-	item.Args = args
-	dataFile, err := os.Create(path.Join(h.dirPath, newDirName, dataFileName))
-	if err != nil {
-		return lib_models.NewInternalError(err)
+	oldItem, ok := h.items[id]
+	item := storageItem{
+		StorageData: models.StorageData{
+			ID:             id,
+			Args:           args,
+			PinnedRevision: pinnedRevision,
+		},
+		Timestamp: time.Now().UTC(),
+		Hash:      getHash(data),
+		dirName:   newDirName,
 	}
-	defer dataFile.Close()
-	err = json.NewEncoder(dataFile).Encode(item)
+	err = writeData(path.Join(h.dirPath, newDirName, dataFileName), item)
 	if err != nil {
 		return lib_models.NewInternalError(err)
 	}
@@ -143,15 +163,98 @@ func (h *Handler) Write(ctx context.Context, id string, args [][2]string, data [
 		return err
 	}
 	h.items[id] = item
-	if oldDirName != "" {
-		if e := os.RemoveAll(path.Join(h.dirPath, oldDirName)); e != nil {
-			h.logger.Error("removing old dir failed", slog_attr.DirNameKey, oldDirName, slog_attr.IDKey, id, attributes.ErrorKey, e, slog_attr.RequestIDKey, reqID)
-		}
+	if ok {
+		h.revisions[id] = append([]storageItem{oldItem}, h.revisions[id]...)
+		h.pruneRevisions(ctx, id)
 	}
 	h.logger.Debug("saved storage item", slog_attr.DirNameKey, newDirName, slog_attr.IDKey, id, slog_attr.RequestIDKey, reqID)
 	return nil
 }
 
+func (h *Handler) SetArgs(ctx context.Context, id string, args [][2]string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	item, ok := h.items[id]
+	if !ok {
+		return lib_models.NewNotFoundError(errors.New("not found"))
+	}
+	item.Args = args
+	if err := h.updateItem(ctx, item); err != nil {
+		return err
+	}
+	h.logger.Debug("updated storage item args", slog_attr.DirNameKey, item.dirName, slog_attr.IDKey, id, slog_attr.RequestIDKey, util.GetReqID(ctx))
+	return nil
+}
+
+func (h *Handler) Pin(ctx context.Context, id, revisionID string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.getRevision(id, revisionID); !ok {
+		return lib_models.NewNotFoundError(errors.New("not found"))
+	}
+	item := h.items[id]
+	item.PinnedRevision = revisionID
+	if err := h.updateItem(ctx, item); err != nil {
+		return err
+	}
+	h.logger.Debug("pinned storage item", slog_attr.IDKey, id, slog_attr.RevisionKey, revisionID, slog_attr.RequestIDKey, util.GetReqID(ctx))
+	return nil
+}
+
+func (h *Handler) Unpin(ctx context.Context, id string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	item, ok := h.items[id]
+	if !ok {
+		return lib_models.NewNotFoundError(errors.New("not found"))
+	}
+	if item.PinnedRevision == "" {
+		return nil
+	}
+	item.PinnedRevision = ""
+	if err := h.updateItem(ctx, item); err != nil {
+		return err
+	}
+	h.logger.Debug("unpinned storage item", slog_attr.IDKey, id, slog_attr.RequestIDKey, util.GetReqID(ctx))
+	return nil
+}
+
+func (h *Handler) Rollback(ctx context.Context, id, revisionID string, args [][2]string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	item, ok := h.getRevision(id, revisionID)
+	if !ok {
+		return lib_models.NewNotFoundError(errors.New("not found"))
+	}
+	if item.dirName == h.items[id].dirName {
+		return lib_models.NewInvalidInputError(errors.New("revision is current"))
+	}
+	doc, err := readDoc(path.Join(h.dirPath, item.dirName, docFileName))
+	if err != nil {
+		return lib_models.NewInternalError(err)
+	}
+	if err = h.write(ctx, id, args, doc, ""); err != nil {
+		return err
+	}
+	h.logger.Debug("rolled back storage item", slog_attr.IDKey, id, slog_attr.RevisionKey, revisionID, slog_attr.RequestIDKey, util.GetReqID(ctx))
+	return nil
+}
+
+func (h *Handler) updateItem(ctx context.Context, item storageItem) error {
+	tmpPath := path.Join(h.dirPath, item.dirName, dataFileName+".tmp")
+	if err := writeData(tmpPath, item); err != nil {
+		if e := os.Remove(tmpPath); e != nil && !os.IsNotExist(e) {
+			h.logger.Error("removing tmp file failed", slog_attr.DirNameKey, item.dirName, slog_attr.IDKey, item.ID, attributes.ErrorKey, e, slog_attr.RequestIDKey, util.GetReqID(ctx))
+		}
+		return lib_models.NewInternalError(err)
+	}
+	if err := os.Rename(tmpPath, path.Join(h.dirPath, item.dirName, dataFileName)); err != nil {
+		return lib_models.NewInternalError(err)
+	}
+	h.items[item.ID] = item
+	return nil
+}
+
 func (h *Handler) Read(_ context.Context, id string) ([]byte, error) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
@@ -159,6 +262,11 @@ func (h *Handler) Read(_ context.Context, id string) ([]byte, error) {
 	if !ok {
 		return nil, lib_models.NewNotFoundError(errors.New("not found"))
 	}
+	if item.PinnedRevision != "" {
+		if revision, ok := h.getRevision(id, item.PinnedRevision); ok {
+			item = revision
+		}
+	}
 	doc, err := readDoc(path.Join(h.dirPath, item.dirName, docFileName))
 	if err != nil {
 		return nil, lib_models.NewInternalError(err)
@@ -178,9 +286,112 @@ func (h *Handler) Delete(_ context.Context, id string) error {
 		return lib_models.NewInternalError(err)
 	}
 	delete(h.items, id)
+	for _, revision := range h.revisions[id] {
+		if e := os.RemoveAll(path.Join(h.dirPath, revision.dirName)); e != nil {
+			h.logger.Error("removing revision dir failed", slog_attr.DirNameKey, revision.dirName, slog_attr.IDKey, id, attributes.ErrorKey, e)
+		}
+	}
+	delete(h.revisions, id)
 	return nil
 }
 
+func (h *Handler) ListRevisions(_ context.Context, id string) ([]lib_models.DocRevision, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	item, ok := h.items[id]
+	if !ok {
+		return nil, lib_models.NewNotFoundError(errors.New("not found"))
+	}
+	revisions := []lib_models.DocRevision{newDocRevision(item, item.PinnedRevision)}
+	for _, revision := range h.revisions[id] {
+		revisions = append(revisions, newDocRevision(revision, item.PinnedRevision))
+	}
+	return revisions, nil
+}
+
+func (h *Handler) ReadRevision(_ context.Context, id, revisionID string) ([]byte, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	item, ok := h.getRevision(id, revisionID)
+	if !ok {
+		return nil, lib_models.NewNotFoundError(errors.New("not found"))
+	}
+	doc, err := readDoc(path.Join(h.dirPath, item.dirName, docFileName))
+	if err != nil {
+		return nil, lib_models.NewInternalError(err)
+	}
+	return doc, nil
+}
+
+func (h *Handler) getRevision(id, revisionID string) (storageItem, bool) {
+	item, ok := h.items[id]
+	if !ok {
+		return storageItem{}, false
+	}
+	if item.dirName == revisionID {
+		return item, true
+	}
+	for _, revision := range h.revisions[id] {
+		if revision.dirName == revisionID {
+			return revision, true
+		}
+	}
+	return storageItem{}, false
+}
+
+func (h *Handler) addItem(item storageItem) {
+	current, ok := h.items[item.ID]
+	if ok && current.Timestamp.After(item.Timestamp) {
+		h.revisions[item.ID] = append(h.revisions[item.ID], item)
+	} else {
+		if ok {
+			h.revisions[item.ID] = append(h.revisions[item.ID], current)
+		}
+		h.items[item.ID] = item
+	}
+	slices.SortFunc(h.revisions[item.ID], func(a, b storageItem) int {
+		return b.Timestamp.Compare(a.Timestamp)
+	})
+}
+
+func (h *Handler) pruneRevisions(ctx context.Context, id string) {
+	revisions := h.revisions[id]
+	n := max(h.maxRevisions-1, 0)
+	if len(revisions) <= n {
+		return
+	}
+	kept := slices.Clone(revisions[:n])
+	for _, revision := range revisions[n:] {
+		if revision.dirName == h.items[id].PinnedRevision {
+			kept = append(kept, revision)
+			continue
+		}
+		if err := os.RemoveAll(path.Join(h.dirPath, revision.dirName)); err != nil {
+			h.logger.Error("removing old dir failed", slog_attr.DirNameKey, revision.dirName, slog_attr.IDKey, id, attributes.ErrorKey, err, slog_attr.RequestIDKey, util.GetReqID(ctx))
+		}
+	}
+	if len(kept) == 0 {
+		delete(h.revisions, id)
+		return
+	}
+	h.revisions[id] = kept
+}
+
+func newDocRevision(item storageItem, pinnedRevision string) lib_models.DocRevision {
+	return lib_models.DocRevision{
+		ID:        item.dirName,
+		Timestamp: item.Timestamp,
+		Hash:      item.Hash,
+		Pinned:    item.dirName == pinnedRevision,
+		Args:      item.Args,
+	}
+}
+
+func getHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
 func genDirName() (string, error) {
 	idObj, err := uuid.NewUUID()
 	if err != nil {
@@ -189,18 +400,27 @@ func genDirName() (string, error) {
 	return idObj.String(), nil
 }
 
-func readData(p string) (models.StorageData, error) {
+func readData(p string) (storageItem, error) {
 	f, err := os.Open(p)
 	if err != nil {
-		return models.StorageData{}, err
+		return storageItem{}, err
 	}
 	defer f.Close()
-	var data models.StorageData
-	err = json.NewDecoder(f).Decode(&data)
+	var item storageItem
+	err = json.NewDecoder(f).Decode(&item)
 	if err != nil {
-		return models.StorageData{}, err
+		return storageItem{}, err
 	}
-	return data, nil
+	return item, nil
+}
+
+func writeData(p string, item storageItem) error {
+	f, err := os.Create(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return json.NewEncoder(f).Encode(item)
 }
 
 func readDoc(p string) ([]byte, error) {
@@ -18,18 +18,22 @@ package storage_hdl
 
 import (
 	"context"
+	"errors"
+	lib_models "github.com/SENERGY-Platform/api-docs-provider/lib/models"
 	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
 	"github.com/SENERGY-Platform/api-docs-provider/pkg/util"
 	"github.com/SENERGY-Platform/go-service-base/struct-logger"
 	"os"
+	"path"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestHandler(t *testing.T) {
 	util.InitLogger(struct_logger.Config{}, os.Stderr, "", "")
 	tmpDir := t.TempDir()
-	hdl := New(tmpDir, "")
+	hdl := New(tmpDir, "", 1)
 	t.Run("write 1", func(t *testing.T) {
 		err := hdl.Write(context.Background(), "id-1", [][2]string{{"key", "/a"}}, []byte("test"))
 		if err != nil {
@@ -42,6 +46,30 @@ func TestHandler(t *testing.T) {
 			t.Error(err)
 		}
 	})
+	t.Run("set args", func(t *testing.T) {
+		err := hdl.SetArgs(context.Background(), "id-2", [][2]string{{"key", "/b"}, {"key2", "c"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := readData(path.Join(tmpDir, hdl.items["id-2"].dirName, dataFileName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		a := models.StorageData{ID: "id-2", Args: [][2]string{{"key", "/b"}, {"key2", "c"}}}
+		if !reflect.DeepEqual(a, data.StorageData) {
+			t.Errorf("expected %v, got %v", a, data.StorageData)
+		}
+		doc, err := hdl.Read(context.Background(), "id-2")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(doc) != "test" {
+			t.Errorf("expected 'test', got '%s'", string(doc))
+		}
+		if err = hdl.SetArgs(context.Background(), "id-2", [][2]string{{"key", "/b"}}); err != nil {
+			t.Fatal(err)
+		}
+	})
 	t.Run("read", func(t *testing.T) {
 		data, err := hdl.Read(context.Background(), "id-1")
 		if err != nil {
@@ -155,7 +183,7 @@ func TestHandler(t *testing.T) {
 		})
 	})
 	t.Run("init", func(t *testing.T) {
-		hdl2 := New(tmpDir, "")
+		hdl2 := New(tmpDir, "", 1)
 		err := hdl2.Init(context.Background())
 		if err != nil {
 			t.Error(err)
@@ -219,6 +247,12 @@ func TestHandler(t *testing.T) {
 					t.Error("expected error")
 				}
 			})
+			t.Run("set args", func(t *testing.T) {
+				err := hdl.SetArgs(context.Background(), "id-1", nil)
+				if err == nil {
+					t.Error("expected error")
+				}
+			})
 		})
 		t.Run("path", func(t *testing.T) {
 			hdl.dirPath = "does-not-exist"
@@ -243,3 +277,223 @@ func TestHandler(t *testing.T) {
 		})
 	})
 }
+
+func TestHandler_Revisions(t *testing.T) {
+	util.InitLogger(struct_logger.Config{}, os.Stderr, "", "")
+	tmpDir := t.TempDir()
+	hdl := New(tmpDir, "", 2)
+	for _, data := range []string{"test-1", "test-2", "test-3"} {
+		if err := hdl.Write(context.Background(), "id-1", [][2]string{{"key", data}}, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	revisions, err := hdl.ListRevisions(context.Background(), "id-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 2 {
+		t.Fatalf("expected 2 revisions, got %d", len(revisions))
+	}
+	if !revisions[0].Timestamp.After(revisions[1].Timestamp) {
+		t.Error("expected revisions sorted newest first")
+	}
+	if revisions[0].Hash != getHash([]byte("test-3")) || revisions[1].Hash != getHash([]byte("test-2")) {
+		t.Error("unexpected revision hashes")
+	}
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("expected 2 dirs, got %d", len(entries))
+	}
+	t.Run("read", func(t *testing.T) {
+		for i, a := range []string{"test-3", "test-2"} {
+			b, err := hdl.ReadRevision(context.Background(), "id-1", revisions[i].ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != a {
+				t.Errorf("expected '%s', got '%s'", a, string(b))
+			}
+		}
+		if _, err = hdl.ReadRevision(context.Background(), "id-1", "test"); err == nil {
+			t.Error("expected error")
+		}
+		if _, err = hdl.ReadRevision(context.Background(), "id-2", revisions[0].ID); err == nil {
+			t.Error("expected error")
+		}
+	})
+	t.Run("init", func(t *testing.T) {
+		hdl2 := New(tmpDir, "", 2)
+		if err := hdl2.Init(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		b, err := hdl2.ListRevisions(context.Background(), "id-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(revisions, b) {
+			t.Errorf("expected %v, got %v", revisions, b)
+		}
+		doc, err := hdl2.Read(context.Background(), "id-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(doc) != "test-3" {
+			t.Errorf("expected 'test-3', got '%s'", string(doc))
+		}
+	})
+	t.Run("init prune", func(t *testing.T) {
+		hdl3 := New(tmpDir, "", 1)
+		if err := hdl3.Init(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		b, err := hdl3.ListRevisions(context.Background(), "id-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) != 1 || b[0].ID != revisions[0].ID {
+			t.Errorf("expected current revision, got %v", b)
+		}
+	})
+	t.Run("delete", func(t *testing.T) {
+		if err := hdl.Write(context.Background(), "id-1", nil, []byte("test-4")); err != nil {
+			t.Fatal(err)
+		}
+		if err := hdl.Delete(context.Background(), "id-1"); err != nil {
+			t.Fatal(err)
+		}
+		entries, err := os.ReadDir(tmpDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 0 {
+			t.Errorf("expected 0 dirs, got %d", len(entries))
+		}
+		if _, err = hdl.ListRevisions(context.Background(), "id-1"); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestHandler_Pin(t *testing.T) {
+	util.InitLogger(struct_logger.Config{}, os.Stderr, "", "")
+	tmpDir := t.TempDir()
+	hdl := New(tmpDir, "", 2)
+	write := func(data string) {
+		if err := hdl.Write(context.Background(), "id-1", [][2]string{{"key", data}}, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	read := func(hdl *Handler, a string) {
+		b, err := hdl.Read(context.Background(), "id-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != a {
+			t.Errorf("expected '%s', got '%s'", a, string(b))
+		}
+	}
+	write("test-1")
+	revisions, err := hdl.ListRevisions(context.Background(), "id-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pinned := revisions[0].ID
+	t.Run("pin", func(t *testing.T) {
+		if err := hdl.Pin(context.Background(), "id-1", pinned); err != nil {
+			t.Fatal(err)
+		}
+		write("test-2")
+		write("test-3")
+		read(hdl, "test-1")
+		items, err := hdl.List(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != 1 || items[0].PinnedRevision != pinned || !reflect.DeepEqual(items[0].Args, [][2]string{{"key", "test-1"}}) || !reflect.DeepEqual(items[0].LatestArgs, [][2]string{{"key", "test-3"}}) {
+			t.Errorf("unexpected items %v", items)
+		}
+		b, err := hdl.ListRevisions(context.Background(), "id-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) != 3 || b[2].ID != pinned || !b[2].Pinned || b[0].Pinned || b[1].Pinned {
+			t.Errorf("expected pinned revision to be kept, got %v", b)
+		}
+		if err = hdl.Pin(context.Background(), "id-1", "test"); err == nil {
+			t.Error("expected error")
+		}
+		if err = hdl.Pin(context.Background(), "id-2", pinned); err == nil {
+			t.Error("expected error")
+		}
+	})
+	t.Run("init", func(t *testing.T) {
+		hdl2 := New(tmpDir, "", 2)
+		if err := hdl2.Init(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		read(hdl2, "test-1")
+		b, err := hdl2.ListRevisions(context.Background(), "id-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) != 3 || !b[2].Pinned {
+			t.Errorf("expected pinned revision to be kept, got %v", b)
+		}
+	})
+	t.Run("unpin", func(t *testing.T) {
+		if err := hdl.Unpin(context.Background(), "id-1"); err != nil {
+			t.Fatal(err)
+		}
+		read(hdl, "test-3")
+		write("test-4")
+		b, err := hdl.ListRevisions(context.Background(), "id-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) != 2 {
+			t.Errorf("expected 2 revisions, got %v", b)
+		}
+		if err = hdl.Unpin(context.Background(), "id-2"); err == nil {
+			t.Error("expected error")
+		}
+	})
+	t.Run("rollback", func(t *testing.T) {
+		b, err := hdl.ListRevisions(context.Background(), "id-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = hdl.Pin(context.Background(), "id-1", b[0].ID); err != nil {
+			t.Fatal(err)
+		}
+		if err = hdl.Rollback(context.Background(), "id-1", b[1].ID, [][2]string{{"key", "rollback"}}); err != nil {
+			t.Fatal(err)
+		}
+		read(hdl, "test-3")
+		items, err := hdl.List(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != 1 || items[0].PinnedRevision != "" || items[0].LatestArgs != nil || !reflect.DeepEqual(items[0].Args, [][2]string{{"key", "rollback"}}) {
+			t.Errorf("unexpected items %v", items)
+		}
+		c, err := hdl.ListRevisions(context.Background(), "id-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(c) != 2 || c[1].ID != b[0].ID {
+			t.Errorf("unexpected revisions %v", c)
+		}
+		var iie *lib_models.InvalidInputError
+		if err = hdl.Rollback(context.Background(), "id-1", c[0].ID, nil); !errors.As(err, &iie) {
+			t.Errorf("expected InvalidInputError, got %v", err)
+		}
+		if err = hdl.Rollback(context.Background(), "id-1", "test", nil); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
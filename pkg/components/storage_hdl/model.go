@@ -16,9 +16,14 @@
 
 package storage_hdl
 
-import "github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+import (
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+	"time"
+)
 
 type storageItem struct {
 	models.StorageData
-	dirName string
+	Timestamp time.Time `json:"timestamp"`
+	Hash      string    `json:"hash"`
+	dirName   string
 }
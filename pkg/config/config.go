@@ -17,22 +17,45 @@
 package config
 
 import (
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
 	sb_config_hdl "github.com/SENERGY-Platform/go-service-base/config-hdl"
 	sb_config_types "github.com/SENERGY-Platform/go-service-base/config-hdl/types"
 	"github.com/SENERGY-Platform/go-service-base/struct-logger"
 	"time"
 )
 
+const (
+	DiscoveryBackendKong            = "kong"
+	DiscoveryBackendKongDeclarative = "kong-declarative"
+	DiscoveryBackendStatic          = "static"
+	DiscoveryBackendKubernetes      = "kubernetes"
+)
+
+var DefaultSwaggerDocPaths = []string{"/doc", "/v3/api-docs", "/openapi.json", "/swagger/doc.json"}
+
 type KongConfig struct {
-	User     string                 `json:"user" env_var:"KONG_USER"`
-	Password sb_config_types.Secret `json:"password" env_var:"KONG_PASSWORD"`
-	BaseURL  string                 `json:"base_url" env_var:"KONG_BASE_URL"`
+	User                  string                 `json:"user" env_var:"KONG_USER"`
+	Password              sb_config_types.Secret `json:"password" env_var:"KONG_PASSWORD"`
+	BaseURL               string                 `json:"base_url" env_var:"KONG_BASE_URL"`
+	PageSize              int                    `json:"page_size" env_var:"KONG_PAGE_SIZE"`
+	Workspaces            []string               `json:"workspaces" env_var:"KONG_WORKSPACES" env_params:"sep=,"`
+	DeclarativeConfigPath string                 `json:"declarative_config_path" env_var:"KONG_DECLARATIVE_CONFIG_PATH"`
 }
 
 type ProcurementConfig struct {
-	SwaggerDocPath string        `json:"swagger_doc_path" env_var:"SWAGGER_DOC_PATH"`
-	Interval       time.Duration `json:"interval" env_var:"PROCUREMENT_INTERVAL"`
-	InitialDelay   time.Duration `json:"initial_delay" env_var:"PROCUREMENT_INITIAL_DELAY"`
+	SwaggerDocPath     string        `json:"swagger_doc_path" env_var:"SWAGGER_DOC_PATH"`
+	SwaggerDocPaths    []string      `json:"swagger_doc_paths" env_var:"SWAGGER_DOC_PATHS" env_params:"sep=,"`
+	Interval           time.Duration `json:"interval" env_var:"PROCUREMENT_INTERVAL"`
+	InitialDelay       time.Duration `json:"initial_delay" env_var:"PROCUREMENT_INITIAL_DELAY"`
+	Concurrency        int           `json:"concurrency" env_var:"PROCUREMENT_CONCURRENCY"`
+	HostRateInterval   time.Duration `json:"host_rate_interval" env_var:"PROCUREMENT_HOST_RATE_INTERVAL"`
+	Retries            int           `json:"retries" env_var:"PROCUREMENT_RETRIES"`
+	RetryDelay         time.Duration `json:"retry_delay" env_var:"PROCUREMENT_RETRY_DELAY"`
+	RetryMaxDelay      time.Duration `json:"retry_max_delay" env_var:"PROCUREMENT_RETRY_MAX_DELAY"`
+	FollowUpInterval   time.Duration `json:"follow_up_interval" env_var:"PROCUREMENT_FOLLOW_UP_INTERVAL"`
+	MissingGracePeriod time.Duration `json:"missing_grace_period" env_var:"PROCUREMENT_MISSING_GRACE_PERIOD"`
+	MaxDeletionPercent int           `json:"max_deletion_percent" env_var:"PROCUREMENT_MAX_DELETION_PERCENT"`
+	MaxReports         int           `json:"max_reports" env_var:"PROCUREMENT_MAX_REPORTS"`
 }
 
 type FilterConfig struct {
@@ -40,14 +63,32 @@ type FilterConfig struct {
 	AdminRoleName string `json:"admin_role_name" env_var:"ADMIN_ROLE_NAME"`
 }
 
+type StaticDiscoveryConfig struct {
+	Path string `json:"path" env_var:"DISCOVERY_STATIC_PATH"`
+}
+
+type KubernetesConfig struct {
+	BaseURL    string   `json:"base_url" env_var:"K8S_BASE_URL"`
+	TokenPath  string   `json:"token_path" env_var:"K8S_TOKEN_PATH"`
+	CAPath     string   `json:"ca_path" env_var:"K8S_CA_PATH"`
+	Namespaces []string `json:"namespaces" env_var:"K8S_NAMESPACES" env_params:"sep=,"`
+	PageSize   int      `json:"page_size" env_var:"K8S_PAGE_SIZE"`
+}
+
 type DiscoveryConfig struct {
-	Kong          KongConfig `json:"kong" env_var:"KONG_CONFIG"`
-	HostBlacklist []string   `json:"host_blacklist" env_var:"DISCOVERY_HOST_BLACKLIST" env_params:"sep=,"`
+	Backends      []string               `json:"backends" env_var:"DISCOVERY_BACKENDS" env_params:"sep=,"`
+	Kong          KongConfig             `json:"kong" env_var:"KONG_CONFIG"`
+	Static        StaticDiscoveryConfig  `json:"static"`
+	Kubernetes    KubernetesConfig       `json:"kubernetes"`
+	HostBlacklist []string               `json:"host_blacklist" env_var:"DISCOVERY_HOST_BLACKLIST" env_params:"sep=,"`
+	Rules         []models.DiscoveryRule `json:"rules" env_var:"DISCOVERY_RULES"`
 }
 
 type StorageConfig struct {
 	SwaggerDataPath  string `json:"swagger_data_path" env_var:"SWAGGER_DATA_PATH"`
 	AsyncapiDataPath string `json:"asyncapi_data_path" env_var:"ASYNCAPI_DATA_PATH"`
+	ReportDataPath   string `json:"report_data_path" env_var:"REPORT_DATA_PATH"`
+	Revisions        int    `json:"revisions" env_var:"STORAGE_REVISIONS"`
 }
 
 type Config struct {
@@ -74,10 +115,32 @@ func New(path string) (*Config, error) {
 		Storage: StorageConfig{
 			SwaggerDataPath:  "swagger-data",
 			AsyncapiDataPath: "asyncapi-data",
+			ReportDataPath:   "report-data",
+			Revisions:        5,
+		},
+		Discovery: DiscoveryConfig{
+			Backends: []string{DiscoveryBackendKong},
+			Kong: KongConfig{
+				PageSize: 100,
+			},
+			Kubernetes: KubernetesConfig{
+				BaseURL:   "https://kubernetes.default.svc",
+				TokenPath: "/var/run/secrets/kubernetes.io/serviceaccount/token",
+				CAPath:    "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt",
+				PageSize:  500,
+			},
 		},
 		Procurement: ProcurementConfig{
-			Interval:     time.Hour * 6,
-			InitialDelay: time.Second * 5,
+			Interval:           time.Hour * 6,
+			InitialDelay:       time.Second * 5,
+			Concurrency:        10,
+			Retries:            3,
+			RetryDelay:         time.Second,
+			RetryMaxDelay:      time.Second * 30,
+			FollowUpInterval:   time.Minute * 5,
+			MissingGracePeriod: time.Hour,
+			MaxDeletionPercent: 50,
+			MaxReports:         50,
 		},
 		HttpTimeout: time.Second * 30,
 	}
@@ -17,6 +17,8 @@
 package config
 
 import (
+	"encoding/json"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
 	sb_config_hdl "github.com/SENERGY-Platform/go-service-base/config-hdl"
 	sb_config_env_parser "github.com/SENERGY-Platform/go-service-base/config-hdl/env_parser"
 	sb_config_types "github.com/SENERGY-Platform/go-service-base/config-hdl/types"
@@ -28,6 +30,7 @@ var envTypeParser = []sb_config_hdl.EnvTypeParser{
 	sb_config_types.SecretEnvTypeParser,
 	sb_config_env_parser.DurationEnvTypeParser,
 	listEnvTypeParser,
+	discoveryRulesEnvTypeParser,
 }
 
 func listEnvTypeParser() (reflect.Type, sb_config_hdl.EnvParser) {
@@ -41,3 +44,15 @@ func listEnvParser(_ reflect.Type, val string, _ []string, kwParams map[string]s
 	}
 	return strings.Split(val, sep), nil
 }
+
+func discoveryRulesEnvTypeParser() (reflect.Type, sb_config_hdl.EnvParser) {
+	return reflect.TypeOf([]models.DiscoveryRule{}), discoveryRulesEnvParser
+}
+
+func discoveryRulesEnvParser(_ reflect.Type, val string, _ []string, _ map[string]string) (interface{}, error) {
+	var rules []models.DiscoveryRule
+	if err := json.Unmarshal([]byte(val), &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
@@ -17,3 +17,8 @@
 package models
 
 const ContextRequestID = "reqID"
+
+const (
+	DocFormatJSON = "json"
+	DocFormatYAML = "yaml"
+)
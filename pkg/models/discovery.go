@@ -0,0 +1,34 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+const (
+	DiscoveryRuleAllow = "allow"
+	DiscoveryRuleDeny  = "deny"
+)
+
+// DiscoveryRule fields host, port, protocol and path are supported by all discovery backends,
+// service and route only by the kong and kong-declarative backends.
+type DiscoveryRule struct {
+	Action   string `json:"action"`
+	Host     string `json:"host,omitempty"`
+	Port     int    `json:"port,omitempty"`
+	Protocol string `json:"protocol,omitempty"`
+	Service  string `json:"service,omitempty"`
+	Route    string `json:"route,omitempty"`
+	Path     string `json:"path,omitempty"`
+}
@@ -16,10 +16,30 @@
 
 package models
 
+import "time"
+
 type Service struct {
-	ID       string
-	Host     string
-	Port     int
+	ID          string
+	Host        string
+	Port        int
+	Protocol    string
+	Workspace   string
+	ExtPaths    []string
+	Denied      bool
+	DeniedPaths []string
+	Routes      map[string]Route
+	DocOptions  DocOptions
+}
+
+type Route struct {
+	Methods   []string
+	Hosts     []string
+	StripPath bool
+}
+
+type DocOptions struct {
+	Path     string
 	Protocol string
-	ExtPaths []string
+	Timeout  time.Duration
+	Disabled bool
 }
@@ -17,6 +17,8 @@
 package models
 
 type StorageData struct {
-	ID   string      `json:"id"`
-	Args [][2]string `json:"args"`
+	ID             string      `json:"id"`
+	Args           [][2]string `json:"args"`
+	PinnedRevision string      `json:"pinned_revision,omitempty"`
+	LatestArgs     [][2]string `json:"-"`
 }
@@ -18,6 +18,7 @@ package asyncapi_srv
 
 import (
 	"context"
+	lib_models "github.com/SENERGY-Platform/api-docs-provider/lib/models"
 	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
 )
 
@@ -26,4 +27,6 @@ type StorageHandler interface {
 	Write(ctx context.Context, id string, args [][2]string, data []byte) error
 	Read(ctx context.Context, id string) ([]byte, error)
 	Delete(ctx context.Context, id string) error
+	ListRevisions(ctx context.Context, id string) ([]lib_models.DocRevision, error)
+	ReadRevision(ctx context.Context, id, revisionID string) ([]byte, error)
 }
@@ -33,6 +33,7 @@ const (
 	titleArgKey       = "title"
 	versionArgKey     = "version"
 	descriptionArgKey = "description"
+	formatArgKey      = "format"
 )
 
 type Service struct {
@@ -90,8 +91,51 @@ func (s *Service) AsyncapiGetDoc(ctx context.Context, id string) ([]byte, error)
 	return rawDoc, nil
 }
 
-func (s *Service) AsyncapiPutDoc(ctx context.Context, id string, data []byte) error {
+func (s *Service) AsyncapiListRevisions(ctx context.Context, id string) ([]lib_models.DocRevision, error) {
+	return s.storageHdl.ListRevisions(ctx, id)
+}
+
+func (s *Service) AsyncapiGetRevision(ctx context.Context, id, revisionID string) ([]byte, error) {
 	reqID := util.GetReqID(ctx)
+	logger.Debug("reading doc revision", slog_attr.IDKey, id, slog_attr.RevisionKey, revisionID, slog_attr.RequestIDKey, reqID)
+	rawDoc, err := s.storageHdl.ReadRevision(ctx, id, revisionID)
+	if err != nil {
+		logger.Error("reading doc revision failed", slog_attr.IDKey, id, slog_attr.RevisionKey, revisionID, attributes.ErrorKey, err.Error(), slog_attr.RequestIDKey, reqID)
+		return nil, err
+	}
+	return rawDoc, nil
+}
+
+func (s *Service) AsyncapiDiff(ctx context.Context, id, from, to string) (lib_models.DocDiff, error) {
+	revisions, err := s.AsyncapiListRevisions(ctx, id)
+	if err != nil {
+		return lib_models.DocDiff{}, err
+	}
+	from, to, err = srv_util.GetDiffRevisions(revisions, from, to)
+	if err != nil {
+		return lib_models.DocDiff{}, err
+	}
+	fromDoc, err := s.AsyncapiGetRevision(ctx, id, from)
+	if err != nil {
+		return lib_models.DocDiff{}, err
+	}
+	toDoc, err := s.AsyncapiGetRevision(ctx, id, to)
+	if err != nil {
+		return lib_models.DocDiff{}, err
+	}
+	return srv_util.NewDocDiff(from, to, fromDoc, toDoc)
+}
+
+func (s *Service) AsyncapiPutDoc(ctx context.Context, id, contentType string, data []byte) error {
+	reqID := util.GetReqID(ctx)
+	format := util.DetectDocFormat(contentType, data)
+	if format == models.DocFormatYAML {
+		var err error
+		if data, err = util.YAMLToJSON(data); err != nil {
+			logger.Error("converting doc failed", slog_attr.IDKey, id, attributes.ErrorKey, err, slog_attr.RequestIDKey, reqID)
+			return lib_models.NewInvalidInputError(err)
+		}
+	}
 	if err := validateDoc(data); err != nil {
 		logger.Error("validating doc failed", slog_attr.IDKey, id, attributes.ErrorKey, err, slog_attr.RequestIDKey, reqID)
 		return lib_models.NewInvalidInputError(err)
@@ -105,6 +149,7 @@ func (s *Service) AsyncapiPutDoc(ctx context.Context, id string, data []byte) er
 		{titleArgKey, aInfo.Title},
 		{versionArgKey, aInfo.Version},
 		{descriptionArgKey, aInfo.Description},
+		{formatArgKey, format},
 	}, data)
 }
 
@@ -134,6 +179,8 @@ func newAsyncapiItem(sd models.StorageData) lib_models.AsyncapiItem {
 			ai.Version = arg[1]
 		case descriptionArgKey:
 			ai.Description = arg[1]
+		case formatArgKey:
+			ai.Format = arg[1]
 		}
 	}
 	return ai
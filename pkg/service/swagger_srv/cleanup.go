@@ -0,0 +1,132 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package swagger_srv
+
+import (
+	"encoding/json"
+)
+
+func cleanupDoc(doc map[string]json.RawMessage, refs map[string]struct{}) error {
+	if err := pruneDocRefs(doc, refs); err != nil {
+		return err
+	}
+	operations, err := getDocOperations(doc)
+	if err != nil {
+		return err
+	}
+	if err = pruneDocTags(doc, operations); err != nil {
+		return err
+	}
+	return pruneDocSecuritySchemes(doc, operations)
+}
+
+func getDocOperations(doc map[string]json.RawMessage) ([]swaggerOperation, error) {
+	var operations []swaggerOperation
+	for _, key := range []string{swaggerPathsKey, swaggerWebhooksKey} {
+		raw, ok := doc[key]
+		if !ok {
+			continue
+		}
+		var pathItems map[string]map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &pathItems); err != nil {
+			return nil, err
+		}
+		for _, pathItem := range pathItems {
+			for _, method := range httpMethods {
+				rawOperation, ok := pathItem[method]
+				if !ok {
+					continue
+				}
+				var operation swaggerOperation
+				if err := json.Unmarshal(rawOperation, &operation); err != nil {
+					return nil, err
+				}
+				operations = append(operations, operation)
+			}
+		}
+	}
+	return operations, nil
+}
+
+func pruneDocTags(doc map[string]json.RawMessage, operations []swaggerOperation) error {
+	raw, ok := doc[swaggerTagsKey]
+	if !ok {
+		return nil
+	}
+	var tags []json.RawMessage
+	if err := json.Unmarshal(raw, &tags); err != nil {
+		return err
+	}
+	usedTags := make(map[string]struct{})
+	for _, operation := range operations {
+		for _, tag := range operation.Tags {
+			usedTags[tag] = struct{}{}
+		}
+	}
+	var newTags []json.RawMessage
+	for _, rawTag := range tags {
+		var tag swaggerTag
+		if err := json.Unmarshal(rawTag, &tag); err != nil {
+			return err
+		}
+		if _, ok = usedTags[tag.Name]; ok {
+			newTags = append(newTags, rawTag)
+		}
+	}
+	if len(newTags) == 0 {
+		delete(doc, swaggerTagsKey)
+		return nil
+	}
+	b, err := json.Marshal(newTags)
+	if err != nil {
+		return err
+	}
+	doc[swaggerTagsKey] = b
+	return nil
+}
+
+func pruneDocSecuritySchemes(doc map[string]json.RawMessage, operations []swaggerOperation) error {
+	cPath := []string{swaggerSecDefsKey}
+	if isOpenApiV3(doc) {
+		cPath = []string{swaggerComponentsKey, swaggerSecSchemesKey}
+	}
+	schemes, err := getContainer(doc, cPath)
+	if err != nil {
+		return err
+	}
+	if schemes == nil {
+		return nil
+	}
+	requirements := make([]map[string][]string, 0)
+	if raw, ok := doc[swaggerSecurityKey]; ok {
+		if err = json.Unmarshal(raw, &requirements); err != nil {
+			return err
+		}
+	}
+	for _, operation := range operations {
+		requirements = append(requirements, operation.Security...)
+	}
+	newSchemes := make(map[string]json.RawMessage)
+	for _, requirement := range requirements {
+		for name := range requirement {
+			if rawScheme, ok := schemes[name]; ok {
+				newSchemes[name] = rawScheme
+			}
+		}
+	}
+	return setContainer(doc, cPath, newSchemes)
+}
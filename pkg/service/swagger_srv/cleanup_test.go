@@ -0,0 +1,104 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package swagger_srv
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func Test_cleanupDoc(t *testing.T) {
+	t.Run("v2", func(t *testing.T) {
+		doc := map[string]json.RawMessage{
+			"swagger":             []byte(`"2.0"`),
+			"info":                []byte(`{}`),
+			"paths":               []byte(`{"/a":{"parameters":[],"get":{"tags":["a"],"security":[{"key":[]}],"responses":{"200":{"$ref":"#/responses/R"}}}}}`),
+			"tags":                []byte(`[{"name":"a","description":"a"},{"name":"b","description":"b"}]`),
+			"securityDefinitions": []byte(`{"key":{"type":"apiKey","name":"k","in":"header"},"basic":{"type":"basic"}}`),
+			"responses":           []byte(`{"R":{"description":"r"},"S":{"description":"s"}}`),
+			"parameters":          []byte(`{"P":{"in":"query","name":"p","type":"string"}}`),
+		}
+		refs := make(map[string]struct{})
+		if err := getRefs(doc[swaggerPathsKey], refs); err != nil {
+			t.Fatal(err)
+		}
+		if err := cleanupDoc(doc, refs); err != nil {
+			t.Fatal(err)
+		}
+		var tags []swaggerTag
+		if err := json.Unmarshal(doc[swaggerTagsKey], &tags); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tags, []swaggerTag{{Name: "a"}}) {
+			t.Errorf("expected tag 'a', got %v", tags)
+		}
+		schemes, err := getContainer(doc, []string{swaggerSecDefsKey})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := schemes["key"]; !ok || len(schemes) != 1 {
+			t.Errorf("expected security definition 'key', got %v", schemes)
+		}
+		responses, err := getContainer(doc, []string{swaggerResponsesKey})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := responses["R"]; !ok || len(responses) != 1 {
+			t.Errorf("expected response 'R', got %v", responses)
+		}
+		if _, ok := doc[swaggerParametersKey]; ok {
+			t.Error("unexpected key 'parameters'")
+		}
+	})
+	t.Run("v3", func(t *testing.T) {
+		doc := map[string]json.RawMessage{
+			"openapi":    []byte(`"3.0.3"`),
+			"info":       []byte(`{}`),
+			"paths":      []byte(`{"/a":{"get":{"tags":["a"],"responses":{"200":{"description":"ok"}}}}}`),
+			"tags":       []byte(`[{"name":"b"}]`),
+			"security":   []byte(`[{"bearer":[]}]`),
+			"components": []byte(`{"securitySchemes":{"bearer":{"type":"http","scheme":"bearer"},"oauth":{"type":"oauth2","flows":{}}}}`),
+		}
+		if err := cleanupDoc(doc, map[string]struct{}{}); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := doc[swaggerTagsKey]; ok {
+			t.Error("unexpected key 'tags'")
+		}
+		schemes, err := getContainer(doc, []string{swaggerComponentsKey, swaggerSecSchemesKey})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := schemes["bearer"]; !ok || len(schemes) != 1 {
+			t.Errorf("expected security scheme 'bearer', got %v", schemes)
+		}
+	})
+	t.Run("no security", func(t *testing.T) {
+		doc := map[string]json.RawMessage{
+			"swagger":             []byte(`"2.0"`),
+			"paths":               []byte(`{"/a":{"get":{}}}`),
+			"securityDefinitions": []byte(`{"basic":{"type":"basic"}}`),
+		}
+		if err := cleanupDoc(doc, map[string]struct{}{}); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := doc[swaggerSecDefsKey]; ok {
+			t.Error("unexpected key 'securityDefinitions'")
+		}
+	})
+}
@@ -20,12 +20,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"net/url"
 	"path"
-	"regexp"
 )
 
-var regRegex = regexp.MustCompile(`\"\$ref\": ?\"#\/definitions\/([^\"]+)\"`)
-
 func (s *Service) filterDoc(ctx context.Context, doc map[string]json.RawMessage, userToken string, userRoles []string) (bool, error) {
 	basePath, err := getBasePath(doc)
 	if err != nil {
@@ -57,14 +55,7 @@ func (s *Service) filterDoc(ctx context.Context, doc map[string]json.RawMessage,
 	if err = setDocPaths(doc, newPaths); err != nil {
 		return false, err
 	}
-	oldDefs, err := getDocDefs(doc)
-	if err != nil {
-		return false, err
-	}
-	if len(oldDefs) == 0 {
-		return true, nil
-	}
-	if err = setDocDefs(doc, getNewDefinitions(oldDefs, allowedRefs)); err != nil {
+	if err = cleanupDoc(doc, allowedRefs); err != nil {
 		return false, err
 	}
 	return true, nil
@@ -78,7 +69,7 @@ func (s *Service) getNewPathsByToken(ctx context.Context, oldPaths map[string]ma
 		return nil, nil, err
 	}
 	newPaths := make(map[string]map[string]json.RawMessage)
-	defRefs := make(map[string]struct{})
+	refs := make(map[string]struct{})
 	for subPath, methods := range oldPaths {
 		allowedMethods := make(map[string]json.RawMessage)
 		fullPath := path.Join(basePath, subPath)
@@ -92,18 +83,20 @@ func (s *Service) getNewPathsByToken(ctx context.Context, oldPaths map[string]ma
 				continue
 			}
 			allowedMethods[method] = rawMessage
-			getDefinitionRefs(rawMessage, defRefs)
+			if err = getRefs(rawMessage, refs); err != nil {
+				return nil, nil, err
+			}
 		}
 		if len(allowedMethods) > 0 {
 			newPaths[subPath] = allowedMethods
 		}
 	}
-	return newPaths, defRefs, nil
+	return newPaths, refs, nil
 }
 
 func (s *Service) getNewPathsByRoles(ctx context.Context, oldPaths map[string]map[string]json.RawMessage, basePath string, userRoles []string) (map[string]map[string]json.RawMessage, map[string]struct{}, error) {
 	newPaths := make(map[string]map[string]json.RawMessage)
-	defRefs := make(map[string]struct{})
+	refs := make(map[string]struct{})
 	for subPath, methods := range oldPaths {
 		allowedMethods := make(map[string]json.RawMessage)
 		fullPath := path.Join(basePath, subPath)
@@ -115,7 +108,9 @@ func (s *Service) getNewPathsByRoles(ctx context.Context, oldPaths map[string]ma
 				}
 				if ok {
 					allowedMethods[method] = rawMessage
-					getDefinitionRefs(rawMessage, defRefs)
+					if err = getRefs(rawMessage, refs); err != nil {
+						return nil, nil, err
+					}
 					break
 				}
 			}
@@ -124,7 +119,7 @@ func (s *Service) getNewPathsByRoles(ctx context.Context, oldPaths map[string]ma
 			newPaths[subPath] = allowedMethods
 		}
 	}
-	return newPaths, defRefs, nil
+	return newPaths, refs, nil
 }
 
 func (s *Service) getAccessPolicyByRole(ctx context.Context, fullPath, role, method string) (bool, error) {
@@ -155,51 +150,6 @@ func setDocPaths(doc map[string]json.RawMessage, newPaths map[string]map[string]
 	return nil
 }
 
-func getDocDefs(doc map[string]json.RawMessage) (map[string]json.RawMessage, error) {
-	rawDefs, ok := doc[swaggerDefinitionsKey]
-	if !ok {
-		return nil, nil
-	}
-	var defs map[string]json.RawMessage
-	if err := json.Unmarshal(rawDefs, &defs); err != nil {
-		return nil, err
-	}
-	return defs, nil
-}
-
-func setDocDefs(doc map[string]json.RawMessage, newDefs map[string]json.RawMessage) error {
-	b, err := json.Marshal(newDefs)
-	if err != nil {
-		return err
-	}
-	doc[swaggerDefinitionsKey] = b
-	return nil
-}
-
-func getNewDefinitions(oldDefs map[string]json.RawMessage, allowedRefs map[string]struct{}) map[string]json.RawMessage {
-	for ref, rawMessage := range oldDefs {
-		if _, ok := allowedRefs[ref]; ok {
-			getDefinitionRefs(rawMessage, allowedRefs)
-		}
-	}
-	newDefs := make(map[string]json.RawMessage)
-	for ref := range allowedRefs {
-		if rawMessage, ok := oldDefs[ref]; ok {
-			newDefs[ref] = rawMessage
-		}
-	}
-	return newDefs
-}
-
-func getDefinitionRefs(raw []byte, refs map[string]struct{}) {
-	res := regRegex.FindAllSubmatch(raw, -1)
-	for _, re := range res {
-		if len(re) > 1 {
-			refs[string(re[1])] = struct{}{}
-		}
-	}
-}
-
 func getPathMethodsMap(oldPaths map[string]map[string]json.RawMessage, basePath string) map[string][]string {
 	pathMethodsMap := make(map[string][]string)
 	for subPath, methods := range oldPaths {
@@ -214,6 +164,9 @@ func getPathMethodsMap(oldPaths map[string]map[string]json.RawMessage, basePath
 }
 
 func getBasePath(doc map[string]json.RawMessage) (string, error) {
+	if isOpenApiV3(doc) {
+		return getOpenApiBasePath(doc)
+	}
 	raw, ok := doc[swaggerBasePathKey]
 	if !ok {
 		return "", errors.New("missing key")
@@ -224,3 +177,25 @@ func getBasePath(doc map[string]json.RawMessage) (string, error) {
 	}
 	return basePath, nil
 }
+
+func getOpenApiBasePath(doc map[string]json.RawMessage) (string, error) {
+	raw, ok := doc[swaggerServersKey]
+	if !ok {
+		return "", errors.New("missing key")
+	}
+	var servers []openApiServer
+	if err := json.Unmarshal(raw, &servers); err != nil {
+		return "", err
+	}
+	if len(servers) == 0 {
+		return "", errors.New("missing servers")
+	}
+	u, err := url.Parse(servers[0].URL)
+	if err != nil {
+		return "", err
+	}
+	if u.Path == "" {
+		return "/", nil
+	}
+	return u.Path, nil
+}
@@ -34,7 +34,7 @@ func TestHandler_filterDoc(t *testing.T) {
 		t.Fatal(err)
 	}
 	ladonClt := &ladonCltMock{}
-	srv := New(nil, nil, nil, ladonClt, 0, "", "")
+	srv := New(context.Background(), nil, nil, nil, ladonClt, nil, 0, "", "", ProcurementOptions{})
 	t.Run("include", func(t *testing.T) {
 		ladonClt.TokenPolicies = map[string][]string{
 			"/t/a": {"get"},
@@ -59,8 +59,8 @@ func TestHandler_filterDoc(t *testing.T) {
 	})
 }
 
-func Test_getNewDefinitions(t *testing.T) {
-	f, err := os.Open("test/swagger.json")
+func TestHandler_filterDocOpenApi(t *testing.T) {
+	f, err := os.Open("test/openapi.json")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -69,12 +69,134 @@ func Test_getNewDefinitions(t *testing.T) {
 	if err = json.NewDecoder(f).Decode(&doc); err != nil {
 		t.Fatal(err)
 	}
-	oldDefs, err := getDocDefs(doc)
-	if err != nil {
-		t.Fatal(err)
+	ladonClt := &ladonCltMock{}
+	srv := New(context.Background(), nil, nil, nil, ladonClt, nil, 0, "", "", ProcurementOptions{})
+	t.Run("include", func(t *testing.T) {
+		ladonClt.TokenPolicies = map[string][]string{
+			"/t/a": {"get"},
+		}
+		ok, err := srv.filterDoc(context.Background(), doc, "test", nil)
+		if err != nil {
+			t.Error(err)
+		}
+		if !ok {
+			t.Error("expected true")
+		}
+		comps, err := getContainer(doc, []string{swaggerComponentsKey})
+		if err != nil {
+			t.Fatal(err)
+		}
+		schemas, err := getContainer(doc, []string{swaggerComponentsKey, "schemas"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(schemas) != 1 {
+			t.Errorf("expected 1 schema, got %d", len(schemas))
+		}
+		if _, ok = schemas["A"]; !ok {
+			t.Error("expected schema 'A'")
+		}
+		for _, section := range []string{"parameters", "requestBodies", "responses"} {
+			if _, ok = comps[section]; ok {
+				t.Errorf("unexpected section '%s'", section)
+			}
+		}
+		if _, ok = comps["securitySchemes"]; !ok {
+			t.Error("expected section 'securitySchemes'")
+		}
+	})
+	t.Run("exclude", func(t *testing.T) {
+		ladonClt.TokenPolicies = map[string][]string{}
+		ok, err := srv.filterDoc(context.Background(), doc, "test", nil)
+		if err != nil {
+			t.Error(err)
+		}
+		if ok {
+			t.Error("expected false")
+		}
+	})
+}
+
+func Test_getBasePath(t *testing.T) {
+	t.Run("v2", func(t *testing.T) {
+		bp, err := getBasePath(map[string]json.RawMessage{
+			"swagger":  []byte(`"2.0"`),
+			"basePath": []byte(`"/t"`),
+		})
+		if err != nil {
+			t.Error(err)
+		}
+		if bp != "/t" {
+			t.Errorf("expected /t, got %s", bp)
+		}
+	})
+	t.Run("v3", func(t *testing.T) {
+		bp, err := getBasePath(map[string]json.RawMessage{
+			"openapi": []byte(`"3.0.3"`),
+			"info":    []byte(`{}`),
+			"paths":   []byte(`{}`),
+			"servers": []byte(`[{"url":"https://test.test/t"}]`),
+		})
+		if err != nil {
+			t.Error(err)
+		}
+		if bp != "/t" {
+			t.Errorf("expected /t, got %s", bp)
+		}
+	})
+	t.Run("v3 root", func(t *testing.T) {
+		bp, err := getBasePath(map[string]json.RawMessage{
+			"openapi": []byte(`"3.0.3"`),
+			"info":    []byte(`{}`),
+			"paths":   []byte(`{}`),
+			"servers": []byte(`[{"url":"https://test.test"}]`),
+		})
+		if err != nil {
+			t.Error(err)
+		}
+		if bp != "/" {
+			t.Errorf("expected /, got %s", bp)
+		}
+	})
+	t.Run("v3 missing servers", func(t *testing.T) {
+		_, err := getBasePath(map[string]json.RawMessage{
+			"openapi": []byte(`"3.0.3"`),
+			"info":    []byte(`{}`),
+			"paths":   []byte(`{}`),
+		})
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func Test_pruneDocRefs(t *testing.T) {
+	readDoc := func(t *testing.T) map[string]json.RawMessage {
+		f, err := os.Open("test/swagger.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		var doc map[string]json.RawMessage
+		if err = json.NewDecoder(f).Decode(&doc); err != nil {
+			t.Fatal(err)
+		}
+		return doc
 	}
 	t.Run("full", func(t *testing.T) {
-		newDefs := getNewDefinitions(oldDefs, map[string]struct{}{"A": {}, "B": {}, "C": {}})
+		doc := readDoc(t)
+		oldDefs, err := getContainer(doc, []string{swaggerDefinitionsKey})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = pruneDocRefs(doc, map[string]struct{}{"/definitions/A": {}, "/definitions/B": {}, "/definitions/C": {}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		newDefs, err := getContainer(doc, []string{swaggerDefinitionsKey})
+		if err != nil {
+			t.Fatal(err)
+		}
 		if len(newDefs) != len(oldDefs) {
 			t.Errorf("expected %d definitions, got %d", len(oldDefs), len(newDefs))
 		}
@@ -85,7 +207,15 @@ func Test_getNewDefinitions(t *testing.T) {
 		}
 	})
 	t.Run("partial", func(t *testing.T) {
-		newDefs := getNewDefinitions(oldDefs, map[string]struct{}{"A": {}})
+		doc := readDoc(t)
+		err := pruneDocRefs(doc, map[string]struct{}{"/definitions/A": {}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		newDefs, err := getContainer(doc, []string{swaggerDefinitionsKey})
+		if err != nil {
+			t.Fatal(err)
+		}
 		if len(newDefs) != 1 {
 			t.Errorf("expected 1 definition, got %d", len(newDefs))
 		}
@@ -93,17 +223,149 @@ func Test_getNewDefinitions(t *testing.T) {
 			t.Error("expected definition 'A'")
 		}
 	})
+	t.Run("transitive", func(t *testing.T) {
+		doc := readDoc(t)
+		err := pruneDocRefs(doc, map[string]struct{}{"/definitions/B/properties/a": {}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		newDefs, err := getContainer(doc, []string{swaggerDefinitionsKey})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(newDefs) != 2 {
+			t.Errorf("expected 2 definitions, got %d", len(newDefs))
+		}
+		for _, key := range []string{"B", "D"} {
+			if _, ok := newDefs[key]; !ok {
+				t.Errorf("missing definition '%s'", key)
+			}
+		}
+	})
 	t.Run("none", func(t *testing.T) {
-		newDefs := getNewDefinitions(oldDefs, map[string]struct{}{})
-		if len(newDefs) != 0 {
-			t.Errorf("expected 0 definitions, got %d", len(newDefs))
+		doc := readDoc(t)
+		err := pruneDocRefs(doc, map[string]struct{}{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := doc[swaggerDefinitionsKey]; ok {
+			t.Error("unexpected key 'definitions'")
+		}
+	})
+	t.Run("shared parameters and responses", func(t *testing.T) {
+		doc := map[string]json.RawMessage{
+			"swagger":     []byte(`"2.0"`),
+			"paths":       []byte(`{"/a":{"get":{"parameters":[{"$ref":"#/parameters/P"}],"responses":{"200":{"$ref":"#/responses/R"}}}}}`),
+			"parameters":  []byte(`{"P":{"in":"body","name":"p","schema":{"$ref":"#/definitions/A"}},"Q":{"in":"query","name":"q","type":"string"}}`),
+			"responses":   []byte(`{"R":{"description":"r","schema":{"$ref":"#/definitions/B"}},"S":{"description":"s"}}`),
+			"definitions": []byte(`{"A":{"type":"string"},"B":{"type":"string"},"C":{"type":"string"}}`),
+		}
+		refs := make(map[string]struct{})
+		if err := getRefs(doc[swaggerPathsKey], refs); err != nil {
+			t.Fatal(err)
+		}
+		if err := pruneDocRefs(doc, refs); err != nil {
+			t.Fatal(err)
+		}
+		for key, a := range map[string][]string{
+			swaggerParametersKey:  {"P"},
+			swaggerResponsesKey:   {"R"},
+			swaggerDefinitionsKey: {"A", "B"},
+		} {
+			items, err := getContainer(doc, []string{key})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(items) != len(a) {
+				t.Errorf("expected %d items in '%s', got %d", len(a), key, len(items))
+			}
+			for _, name := range a {
+				if _, ok := items[name]; !ok {
+					t.Errorf("missing '%s' in '%s'", name, key)
+				}
+			}
+		}
+	})
+	t.Run("openapi", func(t *testing.T) {
+		f, err := os.Open("test/openapi.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		var doc map[string]json.RawMessage
+		if err = json.NewDecoder(f).Decode(&doc); err != nil {
+			t.Fatal(err)
+		}
+		err = pruneDocRefs(doc, map[string]struct{}{"/components/responses/C": {}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		comps, err := getContainer(doc, []string{swaggerComponentsKey})
+		if err != nil {
+			t.Fatal(err)
+		}
+		schemas, err := getContainer(doc, []string{swaggerComponentsKey, "schemas"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(schemas) != 1 {
+			t.Errorf("expected 1 schema, got %d", len(schemas))
+		}
+		if _, ok := schemas["C"]; !ok {
+			t.Error("expected schema 'C'")
+		}
+		for _, section := range []string{"parameters", "requestBodies"} {
+			if _, ok := comps[section]; ok {
+				t.Errorf("unexpected section '%s'", section)
+			}
+		}
+		for _, section := range []string{"responses", "securitySchemes"} {
+			if _, ok := comps[section]; !ok {
+				t.Errorf("expected section '%s'", section)
+			}
 		}
 	})
 }
 
+func Test_getRefs(t *testing.T) {
+	raw := []byte(`{
+		"a": {"$ref" :   "#/definitions/A"},
+		"b": {"$ref": "#\/definitions\/B"},
+		"c": {"allOf": [{"$ref": "#/components/schemas/C"}, {"type": "object"}]},
+		"d": {"discriminator": {"propertyName": "t", "mapping": {"x": "#/components/schemas/D", "y": "Y"}}},
+		"e": {"$ref": "#/parameters/E"},
+		"f": {"$ref": "#/paths/~1a~1b/get"},
+		"g": {"$ref": "#/definitions/G%20H"},
+		"h": {"$ref": "other.json#/definitions/H"},
+		"i": {"$ref": "https://test.test/schema.json"},
+		"j": {"properties": {"$ref": {"type": "string"}}}
+	}`)
+	refs := make(map[string]struct{})
+	if err := getRefs(raw, refs); err != nil {
+		t.Fatal(err)
+	}
+	a := map[string]struct{}{
+		"/definitions/A":        {},
+		"/definitions/B":        {},
+		"/components/schemas/C": {},
+		"/components/schemas/D": {},
+		"/parameters/E":         {},
+		"/paths/~1a~1b/get":     {},
+		"/definitions/G H":      {},
+	}
+	if len(refs) != len(a) {
+		t.Errorf("expected %d references, got %d: %v", len(a), len(refs), refs)
+	}
+	for ref := range a {
+		if _, ok := refs[ref]; !ok {
+			t.Errorf("missing reference '%s'", ref)
+		}
+	}
+}
+
 func TestHandler_getNewPathsByRoles(t *testing.T) {
 	ladonClt := &ladonCltMock{}
-	srv := New(nil, nil, nil, ladonClt, 0, "", "")
+	srv := New(context.Background(), nil, nil, nil, ladonClt, nil, 0, "", "", ProcurementOptions{})
 	f, err := os.Open("test/swagger.json")
 	if err != nil {
 		t.Fatal(err)
@@ -149,7 +411,7 @@ func TestHandler_getNewPathsByRoles(t *testing.T) {
 		if len(allowedRefs) != 3 {
 			t.Errorf("expected 3 references, got %d", len(newPaths))
 		}
-		for _, s := range []string{"A", "B", "C"} {
+		for _, s := range []string{"/definitions/A", "/definitions/B", "/definitions/C"} {
 			if _, ok := allowedRefs[s]; !ok {
 				t.Errorf("missing reference '%s'", s)
 			}
@@ -179,8 +441,8 @@ func TestHandler_getNewPathsByRoles(t *testing.T) {
 		if len(allowedRefs) != 1 {
 			t.Errorf("expected 1 reference, got %d", len(newPaths))
 		}
-		if _, ok := allowedRefs["A"]; !ok {
-			t.Error("missing reference 'A'")
+		if _, ok := allowedRefs["/definitions/A"]; !ok {
+			t.Error("missing reference '/definitions/A'")
 		}
 	})
 	t.Run("none", func(t *testing.T) {
@@ -200,7 +462,7 @@ func TestHandler_getNewPathsByRoles(t *testing.T) {
 
 func TestHandler_getNewPathsByToken(t *testing.T) {
 	ladonClt := &ladonCltMock{}
-	srv := New(nil, nil, nil, ladonClt, 0, "", "")
+	srv := New(context.Background(), nil, nil, nil, ladonClt, nil, 0, "", "", ProcurementOptions{})
 	f, err := os.Open("test/swagger.json")
 	if err != nil {
 		t.Fatal(err)
@@ -246,7 +508,7 @@ func TestHandler_getNewPathsByToken(t *testing.T) {
 		if len(allowedRefs) != 3 {
 			t.Errorf("expected 3 references, got %d", len(newPaths))
 		}
-		for _, s := range []string{"A", "B", "C"} {
+		for _, s := range []string{"/definitions/A", "/definitions/B", "/definitions/C"} {
 			if _, ok := allowedRefs[s]; !ok {
 				t.Errorf("missing reference '%s'", s)
 			}
@@ -276,8 +538,8 @@ func TestHandler_getNewPathsByToken(t *testing.T) {
 		if len(allowedRefs) != 1 {
 			t.Errorf("expected 1 reference, got %d", len(newPaths))
 		}
-		if _, ok := allowedRefs["A"]; !ok {
-			t.Error("missing reference 'A'")
+		if _, ok := allowedRefs["/definitions/A"]; !ok {
+			t.Error("missing reference '/definitions/A'")
 		}
 	})
 	t.Run("none", func(t *testing.T) {
@@ -0,0 +1,52 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package swagger_srv
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+	"slices"
+)
+
+func getConfigHash(service models.Service, apiGtwHost string) (string, error) {
+	extPaths := slices.Clone(service.ExtPaths)
+	slices.Sort(extPaths)
+	b, err := json.Marshal(struct {
+		ExtPaths   []string
+		Routes     map[string]models.Route
+		Workspace  string
+		DocOptions models.DocOptions
+		ApiGtwHost string
+	}{
+		ExtPaths:   extPaths,
+		Routes:     service.Routes,
+		Workspace:  service.Workspace,
+		DocOptions: service.DocOptions,
+		ApiGtwHost: apiGtwHost,
+	})
+	if err != nil {
+		return "", err
+	}
+	return getHash(b), nil
+}
+
+func getHash(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package swagger_srv
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHostLimiter_Wait(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		l := newHostLimiter(0)
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			if err := l.Wait(context.Background(), "a"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if d := time.Since(start); d > time.Millisecond*10 {
+			t.Errorf("expected no delay, got %s", d)
+		}
+	})
+	t.Run("interval", func(t *testing.T) {
+		l := newHostLimiter(time.Millisecond * 20)
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			if err := l.Wait(context.Background(), "a"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if d := time.Since(start); d < time.Millisecond*40 {
+			t.Errorf("expected delay of at least 40ms, got %s", d)
+		}
+		start = time.Now()
+		if err := l.Wait(context.Background(), "b"); err != nil {
+			t.Fatal(err)
+		}
+		if d := time.Since(start); d > time.Millisecond*10 {
+			t.Errorf("expected no delay for other host, got %s", d)
+		}
+	})
+	t.Run("canceled", func(t *testing.T) {
+		l := newHostLimiter(time.Second)
+		if err := l.Wait(context.Background(), "a"); err != nil {
+			t.Fatal(err)
+		}
+		ctx, cf := context.WithTimeout(context.Background(), time.Millisecond*10)
+		defer cf()
+		if err := l.Wait(ctx, "a"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded, got %v", err)
+		}
+	})
+}
@@ -18,6 +18,7 @@ package swagger_srv
 
 import (
 	"context"
+	lib_models "github.com/SENERGY-Platform/api-docs-provider/lib/models"
 	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
 )
 
@@ -28,6 +29,18 @@ type DiscoveryHandler interface {
 type StorageHandler interface {
 	List(ctx context.Context) ([]models.StorageData, error)
 	Write(ctx context.Context, id string, args [][2]string, data []byte) error
+	SetArgs(ctx context.Context, id string, args [][2]string) error
 	Read(ctx context.Context, id string) ([]byte, error)
 	Delete(ctx context.Context, id string) error
+	ListRevisions(ctx context.Context, id string) ([]lib_models.DocRevision, error)
+	ReadRevision(ctx context.Context, id, revisionID string) ([]byte, error)
+	Pin(ctx context.Context, id, revisionID string) error
+	Unpin(ctx context.Context, id string) error
+	Rollback(ctx context.Context, id, revisionID string, args [][2]string) error
+}
+
+type ReportHandler interface {
+	Put(ctx context.Context, run lib_models.ProcurementRun) error
+	Get(ctx context.Context, id string) (lib_models.ProcurementRun, error)
+	List(ctx context.Context) ([]lib_models.ProcurementRunInfo, error)
 }
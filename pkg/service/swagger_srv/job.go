@@ -0,0 +1,143 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package swagger_srv
+
+import (
+	"context"
+	"errors"
+	lib_models "github.com/SENERGY-Platform/api-docs-provider/lib/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util/slog_attr"
+	"github.com/google/uuid"
+	"sync"
+	"time"
+)
+
+const jobRetention = time.Hour
+
+type job struct {
+	info   lib_models.ProcurementJob
+	report *runReport
+	cancel context.CancelFunc
+	mu     sync.RWMutex
+}
+
+func (j *job) get() lib_models.ProcurementJob {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	info := j.info
+	if info.State == lib_models.ProcurementJobStateRunning {
+		info.Progress = j.report.progress()
+	}
+	return info
+}
+
+func (j *job) finish(err error, canceled bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	end := time.Now().UTC()
+	j.info.End = &end
+	j.info.Progress = j.report.progress()
+	switch {
+	case canceled:
+		j.info.State = lib_models.ProcurementJobStateCanceled
+	case err != nil:
+		j.info.State = lib_models.ProcurementJobStateFailed
+		j.info.Error = err.Error()
+	default:
+		j.info.State = lib_models.ProcurementJobStateCompleted
+	}
+}
+
+func (j *job) isFinished() (bool, time.Time) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	if j.info.End == nil {
+		return false, time.Time{}
+	}
+	return true, *j.info.End
+}
+
+func (s *Service) SwaggerStartRefreshDocs(ctx context.Context, force bool) (lib_models.ProcurementJob, error) {
+	if !s.mu.TryLock() {
+		return lib_models.ProcurementJob{}, lib_models.NewResourceBusyError(errors.New("procurement running"))
+	}
+	jCtx, cf := context.WithCancel(context.WithValue(s.ctx, models.ContextRequestID, ctx.Value(models.ContextRequestID)))
+	report := newRunReport(lib_models.ProcurementRunTypeFull)
+	j := &job{
+		info: lib_models.ProcurementJob{
+			ID:    uuid.NewString(),
+			State: lib_models.ProcurementJobStateRunning,
+			Start: time.Now().UTC(),
+			RunID: report.run.ID,
+		},
+		report: report,
+		cancel: cf,
+	}
+	s.jobsMu.Lock()
+	s.pruneJobs()
+	s.jobs[j.info.ID] = j
+	s.jobsMu.Unlock()
+	logger.Info("starting procurement job", slog_attr.IDKey, j.info.ID, slog_attr.RequestIDKey, util.GetReqID(ctx))
+	go func() {
+		defer s.mu.Unlock()
+		defer cf()
+		err := s.refreshDocs(jCtx, report, force)
+		j.finish(err, jCtx.Err() != nil)
+	}()
+	return j.get(), nil
+}
+
+func (s *Service) SwaggerGetRefreshJob(_ context.Context, id string) (lib_models.ProcurementJob, error) {
+	j, err := s.getJob(id)
+	if err != nil {
+		return lib_models.ProcurementJob{}, err
+	}
+	return j.get(), nil
+}
+
+func (s *Service) SwaggerCancelRefreshJob(ctx context.Context, id string) error {
+	j, err := s.getJob(id)
+	if err != nil {
+		return err
+	}
+	if ok, _ := j.isFinished(); ok {
+		return lib_models.NewInvalidInputError(errors.New("job not running"))
+	}
+	logger.Info("canceling procurement job", slog_attr.IDKey, id, slog_attr.RequestIDKey, util.GetReqID(ctx))
+	j.cancel()
+	return nil
+}
+
+func (s *Service) getJob(id string) (*job, error) {
+	s.jobsMu.RLock()
+	defer s.jobsMu.RUnlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return nil, lib_models.NewNotFoundError(errors.New("job not found"))
+	}
+	return j, nil
+}
+
+func (s *Service) pruneJobs() {
+	for id, j := range s.jobs {
+		if ok, end := j.isFinished(); ok && time.Since(end) > jobRetention {
+			delete(s.jobs, id)
+		}
+	}
+}
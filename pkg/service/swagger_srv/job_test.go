@@ -0,0 +1,203 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package swagger_srv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	lib_models "github.com/SENERGY-Platform/api-docs-provider/lib/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util"
+	struct_logger "github.com/SENERGY-Platform/go-service-base/struct-logger"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestService_RefreshJob(t *testing.T) {
+	validDoc, err := os.ReadFile("test/swagger.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	docClt := &docCltMock{
+		Docs:  make(map[string][]byte),
+		Delay: time.Millisecond * 20,
+	}
+	discoveryHdl := &discoveryHdlMock{
+		Services: make(map[string]models.Service),
+	}
+	for i := 0; i < 8; i++ {
+		id := fmt.Sprintf("ph%d", i)
+		docClt.Docs[id+"/doc"] = validDoc
+		discoveryHdl.Services[id] = models.Service{
+			ID:       id,
+			Host:     "h",
+			Port:     i,
+			Protocol: "p",
+			ExtPaths: []string{"/t"},
+		}
+	}
+	util.InitLogger(struct_logger.Config{}, os.Stderr, "", "")
+	InitLogger()
+	t.Run("completed", func(t *testing.T) {
+		storageHdl := &storageHdlMock{}
+		reportHdl := &reportHdlMock{}
+		srv := New(context.Background(), storageHdl, discoveryHdl, docClt, nil, reportHdl, time.Second, "test.test", "", ProcurementOptions{DocPaths: []string{"/doc"}, Concurrency: 1})
+		job, err := srv.SwaggerStartRefreshDocs(context.Background(), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.State != lib_models.ProcurementJobStateRunning || job.End != nil {
+			t.Errorf("unexpected job %+v", job)
+		}
+		if _, err = srv.SwaggerStartRefreshDocs(context.Background(), false); err == nil {
+			t.Error("expected error")
+		} else {
+			var rbe *lib_models.ResourceBusyError
+			if !errors.As(err, &rbe) {
+				t.Errorf("expected ResourceBusyError, got %T", err)
+			}
+		}
+		if err = srv.SwaggerRefreshDocs(context.Background(), false); err == nil {
+			t.Error("expected error")
+		}
+		job = waitForJob(t, srv, job.ID)
+		if job.State != lib_models.ProcurementJobStateCompleted {
+			t.Errorf("expected state %s, got %s", lib_models.ProcurementJobStateCompleted, job.State)
+		}
+		a := lib_models.ProcurementJobProgress{Total: 8, Done: 8}
+		if job.Progress != a {
+			t.Errorf("expected %+v, got %+v", a, job.Progress)
+		}
+		if len(storageHdl.Items) != 8 {
+			t.Errorf("expected 8 items, got %d", len(storageHdl.Items))
+		}
+		if _, err = srv.SwaggerGetProcurementRun(context.Background(), job.RunID); err != nil {
+			t.Error(err)
+		}
+		if err = srv.SwaggerCancelRefreshJob(context.Background(), job.ID); err == nil {
+			t.Error("expected error")
+		} else {
+			var iie *lib_models.InvalidInputError
+			if !errors.As(err, &iie) {
+				t.Errorf("expected InvalidInputError, got %T", err)
+			}
+		}
+	})
+	t.Run("canceled", func(t *testing.T) {
+		storageHdl := &storageHdlMock{}
+		srv := New(context.Background(), storageHdl, discoveryHdl, docClt, nil, &reportHdlMock{}, time.Second, "test.test", "", ProcurementOptions{DocPaths: []string{"/doc"}, Concurrency: 1})
+		job, err := srv.SwaggerStartRefreshDocs(context.Background(), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond * 30)
+		if err = srv.SwaggerCancelRefreshJob(context.Background(), job.ID); err != nil {
+			t.Fatal(err)
+		}
+		job = waitForJob(t, srv, job.ID)
+		if job.State != lib_models.ProcurementJobStateCanceled {
+			t.Errorf("expected state %s, got %s", lib_models.ProcurementJobStateCanceled, job.State)
+		}
+		if job.Progress.Done >= 8 {
+			t.Errorf("expected less than 8 done, got %d", job.Progress.Done)
+		}
+		if err = srv.SwaggerRefreshDocs(context.Background(), false); err != nil {
+			t.Error(err)
+		}
+	})
+	t.Run("service context canceled", func(t *testing.T) {
+		ctx, cf := context.WithCancel(context.Background())
+		defer cf()
+		srv := New(ctx, &storageHdlMock{}, discoveryHdl, docClt, nil, &reportHdlMock{}, time.Second, "test.test", "", ProcurementOptions{DocPaths: []string{"/doc"}, Concurrency: 1})
+		reqCtx, reqCf := context.WithCancel(context.Background())
+		job, err := srv.SwaggerStartRefreshDocs(reqCtx, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reqCf()
+		time.Sleep(time.Millisecond * 30)
+		if job, err = srv.SwaggerGetRefreshJob(context.Background(), job.ID); err != nil {
+			t.Fatal(err)
+		}
+		if job.State != lib_models.ProcurementJobStateRunning {
+			t.Errorf("expected state %s, got %s", lib_models.ProcurementJobStateRunning, job.State)
+		}
+		cf()
+		job = waitForJob(t, srv, job.ID)
+		if job.State != lib_models.ProcurementJobStateCanceled {
+			t.Errorf("expected state %s, got %s", lib_models.ProcurementJobStateCanceled, job.State)
+		}
+		if job.Progress.Done >= 8 {
+			t.Errorf("expected less than 8 done, got %d", job.Progress.Done)
+		}
+	})
+	t.Run("sync service context canceled", func(t *testing.T) {
+		ctx, cf := context.WithCancel(context.Background())
+		defer cf()
+		storageHdl := &storageHdlMock{}
+		srv := New(ctx, storageHdl, discoveryHdl, docClt, nil, &reportHdlMock{}, time.Second, "test.test", "", ProcurementOptions{DocPaths: []string{"/doc"}, Concurrency: 1})
+		time.AfterFunc(time.Millisecond*30, cf)
+		if err = srv.SwaggerRefreshDocs(context.Background(), false); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context canceled, got %v", err)
+		}
+		if len(storageHdl.Items) >= 8 {
+			t.Errorf("expected less than 8 items, got %d", len(storageHdl.Items))
+		}
+	})
+	t.Run("failed", func(t *testing.T) {
+		srv := New(context.Background(), &storageHdlMock{}, &discoveryHdlMock{Err: errors.New("test")}, docClt, nil, &reportHdlMock{}, time.Second, "test.test", "", ProcurementOptions{DocPaths: []string{"/doc"}})
+		job, err := srv.SwaggerStartRefreshDocs(context.Background(), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		job = waitForJob(t, srv, job.ID)
+		if job.State != lib_models.ProcurementJobStateFailed || job.Error == "" {
+			t.Errorf("unexpected job %+v", job)
+		}
+	})
+	t.Run("not found", func(t *testing.T) {
+		srv := New(context.Background(), nil, nil, nil, nil, nil, 0, "", "", ProcurementOptions{})
+		var nfe *lib_models.NotFoundError
+		if _, err := srv.SwaggerGetRefreshJob(context.Background(), "test"); !errors.As(err, &nfe) {
+			t.Errorf("expected NotFoundError, got %v", err)
+		}
+		if err := srv.SwaggerCancelRefreshJob(context.Background(), "test"); !errors.As(err, &nfe) {
+			t.Errorf("expected NotFoundError, got %v", err)
+		}
+	})
+}
+
+func waitForJob(t *testing.T, srv *Service, id string) lib_models.ProcurementJob {
+	t.Helper()
+	timeout := time.After(time.Second * 5)
+	for {
+		job, err := srv.SwaggerGetRefreshJob(context.Background(), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.End != nil {
+			return job
+		}
+		select {
+		case <-timeout:
+			t.Fatal("job did not finish")
+		case <-time.After(time.Millisecond * 5):
+		}
+	}
+}
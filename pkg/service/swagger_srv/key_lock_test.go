@@ -0,0 +1,75 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package swagger_srv
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_keyLock(t *testing.T) {
+	l := newKeyLock()
+	t.Run("same key", func(t *testing.T) {
+		unlock := l.Lock("a")
+		locked := make(chan struct{})
+		go func() {
+			defer close(locked)
+			l.Lock("a")()
+		}()
+		select {
+		case <-locked:
+			t.Fatal("expected lock to block")
+		case <-time.After(time.Millisecond * 20):
+		}
+		unlock()
+		select {
+		case <-locked:
+		case <-time.After(time.Second):
+			t.Fatal("expected lock to be released")
+		}
+	})
+	t.Run("different keys", func(t *testing.T) {
+		unlock := l.Lock("a")
+		defer unlock()
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			l.Lock("b")()
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("expected lock not to block")
+		}
+	})
+	t.Run("cleanup", func(t *testing.T) {
+		wg := sync.WaitGroup{}
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				l.Lock("c")()
+			}()
+		}
+		wg.Wait()
+		l.Lock("a")()
+		if len(l.locks) != 0 {
+			t.Errorf("expected 0 locks, got %d", len(l.locks))
+		}
+	})
+}
@@ -18,6 +18,8 @@ package swagger_srv
 
 import (
 	"encoding/json"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/components/doc_clt"
+	"time"
 )
 
 const (
@@ -29,8 +31,19 @@ const (
 	swaggerSchemesKey     = "schemes"
 	swaggerPathsKey       = "paths"
 	swaggerDefinitionsKey = "definitions"
+	swaggerServersKey     = "servers"
+	swaggerComponentsKey  = "components"
+	swaggerParametersKey  = "parameters"
+	swaggerResponsesKey   = "responses"
+	swaggerWebhooksKey    = "webhooks"
+	swaggerTagsKey        = "tags"
+	swaggerSecurityKey    = "security"
+	swaggerSecDefsKey     = "securityDefinitions"
+	swaggerSecSchemesKey  = "securitySchemes"
 )
 
+const defaultScheme = "https"
+
 var swaggerV2Keys = []string{
 	swaggerKey,
 	swaggerInfoKey,
@@ -43,6 +56,57 @@ var swaggerV3Keys = []string{
 	swaggerPathsKey,
 }
 
+var httpMethods = []string{
+	"get",
+	"put",
+	"post",
+	"delete",
+	"options",
+	"head",
+	"patch",
+	"trace",
+}
+
+var openApiRefComponents = []string{
+	"schemas",
+	"responses",
+	"parameters",
+	"examples",
+	"requestBodies",
+	"headers",
+	"links",
+	"callbacks",
+	"pathItems",
+}
+
+type ProcurementOptions struct {
+	DocPaths           []string
+	Concurrency        int
+	HostRateInterval   time.Duration
+	Retries            int
+	RetryDelay         time.Duration
+	RetryMaxDelay      time.Duration
+	FollowUpInterval   time.Duration
+	MissingGracePeriod time.Duration
+	MaxDeletionPercent int
+}
+
+type serviceState struct {
+	docPath    string
+	validators doc_clt.Validators
+}
+
+type serviceDoc struct {
+	doc        doc_clt.Doc
+	docPath    string
+	configHash string
+	data       map[string]json.RawMessage
+	info       swaggerInfo
+	paths      map[string]map[string]json.RawMessage
+	basePath   string
+	isV3       bool
+}
+
 type docWrapper struct {
 	basePath string
 	doc      map[string]json.RawMessage
@@ -52,6 +116,20 @@ type swaggerDoc struct {
 	Info swaggerInfo `json:"info"`
 }
 
+type swaggerOperation struct {
+	Tags     []string              `json:"tags"`
+	Security []map[string][]string `json:"security"`
+}
+
+type swaggerTag struct {
+	Name string `json:"name"`
+}
+
+type openApiServer struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
 type swaggerInfo struct {
 	Title       string `json:"title"`
 	Version     string `json:"version"`
@@ -22,11 +22,15 @@ import (
 	"errors"
 	"fmt"
 	lib_models "github.com/SENERGY-Platform/api-docs-provider/lib/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/components/doc_clt"
 	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
 	srv_util "github.com/SENERGY-Platform/api-docs-provider/pkg/service/util"
 	"github.com/SENERGY-Platform/api-docs-provider/pkg/util"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util/doc_diff"
 	"github.com/SENERGY-Platform/api-docs-provider/pkg/util/slog_attr"
 	"github.com/SENERGY-Platform/go-service-base/struct-logger/attributes"
+	"maps"
+	"net/url"
 	"path"
 	"runtime/debug"
 	"slices"
@@ -46,24 +50,55 @@ func (s *Service) SwaggerPeriodicProcurement(ctx context.Context, interval, dela
 		logger.Info("periodic procurement halted")
 	}()
 	timer := time.NewTimer(delay)
+	nextRun := time.Now().Add(delay)
+	var followUpTimer *time.Timer
+	var followUpC <-chan time.Time
+	var followUpDelay time.Duration
+	scheduleFollowUp := func(d time.Duration) {
+		if followUpTimer != nil {
+			followUpTimer.Stop()
+			followUpTimer = nil
+			followUpC = nil
+		}
+		if d <= 0 || time.Now().Add(d).After(nextRun) || !s.hasFailedServices() {
+			return
+		}
+		followUpDelay = d
+		followUpTimer = time.NewTimer(d)
+		followUpC = followUpTimer.C
+	}
 	loop := true
 	for loop {
 		select {
 		case <-timer.C:
-			err := s.SwaggerRefreshDocs(ctx)
+			err := s.SwaggerRefreshDocs(ctx, false)
 			if err != nil {
 				var rbe *lib_models.ResourceBusyError
-				if !errors.As(err, &rbe) {
+				if !errors.As(err, &rbe) && ctx.Err() == nil {
 					logger.Error("procurement failed", attributes.ErrorKey, err)
 				}
 			}
 			timer.Reset(interval)
+			nextRun = time.Now().Add(interval)
+			scheduleFollowUp(s.procOpts.FollowUpInterval)
+		case <-followUpC:
+			err := s.refreshFailedServices(ctx)
+			if err != nil {
+				var rbe *lib_models.ResourceBusyError
+				if !errors.As(err, &rbe) {
+					logger.Error("follow-up procurement failed", attributes.ErrorKey, err)
+				}
+			}
+			scheduleFollowUp(followUpDelay * 2)
 		case <-ctx.Done():
 			loop = false
 			logger.Info("stopping periodic procurement")
 			break
 		}
 	}
+	if followUpTimer != nil {
+		followUpTimer.Stop()
+	}
 	if !timer.Stop() {
 		select {
 		case <-timer.C:
@@ -73,111 +108,557 @@ func (s *Service) SwaggerPeriodicProcurement(ctx context.Context, interval, dela
 	return lErr
 }
 
-func (s *Service) SwaggerRefreshDocs(ctx context.Context) error {
+func (s *Service) SwaggerRefreshDocs(ctx context.Context, force bool) error {
 	if !s.mu.TryLock() {
 		return lib_models.NewResourceBusyError(errors.New("procurement running"))
 	}
 	defer s.mu.Unlock()
+	ctx, cf := context.WithCancel(ctx)
+	defer cf()
+	stop := context.AfterFunc(s.ctx, cf)
+	defer stop()
+	return s.refreshDocs(ctx, newRunReport(lib_models.ProcurementRunTypeFull), force)
+}
+
+func (s *Service) refreshDocs(ctx context.Context, report *runReport, force bool) error {
+	s.running.Store(true)
+	defer s.running.Store(false)
 	services, err := s.discoveryHdl.GetServices(ctx)
 	if err != nil {
+		s.saveReport(ctx, report, err)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		return lib_models.NewInternalError(err)
 	}
+	report.setDiscovered(len(services))
+	storedItems, err := s.getStoredItems(ctx)
+	if err != nil {
+		logger.Error("listing stored docs failed", attributes.ErrorKey, err, slog_attr.RequestIDKey, util.GetReqID(ctx))
+	}
+	s.setFailedServices(s.procureServices(ctx, services, storedItems, report))
+	if err = ctx.Err(); err != nil {
+		s.saveReport(ctx, report, err)
+		return err
+	}
+	skipped, err := s.cleanOldServices(ctx, services, force)
+	if err != nil {
+		logger.Error("removing old docs failed", attributes.ErrorKey, err, slog_attr.RequestIDKey, util.GetReqID(ctx))
+	}
+	report.setSkippedDeletions(skipped)
+	s.saveReport(ctx, report, nil)
+	return nil
+}
+
+func (s *Service) refreshFailedServices(ctx context.Context) error {
+	if !s.mu.TryLock() {
+		return lib_models.NewResourceBusyError(errors.New("procurement running"))
+	}
+	defer s.mu.Unlock()
+	s.failedMu.RLock()
+	services := maps.Clone(s.failed)
+	s.failedMu.RUnlock()
+	if len(services) == 0 {
+		return nil
+	}
+	s.running.Store(true)
+	defer s.running.Store(false)
+	logger.Info("retrying failed services", slog_attr.NumberKey, len(services), slog_attr.RequestIDKey, util.GetReqID(ctx))
+	report := newRunReport(lib_models.ProcurementRunTypeFollowUp)
+	report.setDiscovered(len(services))
+	storedItems, err := s.getStoredItems(ctx)
+	if err != nil {
+		logger.Error("listing stored docs failed", attributes.ErrorKey, err, slog_attr.RequestIDKey, util.GetReqID(ctx))
+	}
+	s.setFailedServices(s.procureServices(ctx, services, storedItems, report))
+	s.saveReport(ctx, report, ctx.Err())
+	return nil
+}
+
+func (s *Service) SwaggerRefreshService(ctx context.Context, ref string) (lib_models.ProcurementRun, error) {
+	services, err := s.discoveryHdl.GetServices(ctx)
+	if err != nil {
+		return lib_models.ProcurementRun{}, lib_models.NewInternalError(err)
+	}
+	services = findServices(services, ref)
+	if len(services) == 0 {
+		return lib_models.ProcurementRun{}, lib_models.NewNotFoundError(errors.New("service not found"))
+	}
+	logger.Info("refreshing service", slog_attr.IDKey, ref, slog_attr.NumberKey, len(services), slog_attr.RequestIDKey, util.GetReqID(ctx))
+	report := newRunReport(lib_models.ProcurementRunTypeService)
+	report.setDiscovered(len(services))
+	storedItems, err := s.getStoredItems(ctx)
+	if err != nil {
+		logger.Error("listing stored docs failed", attributes.ErrorKey, err, slog_attr.RequestIDKey, util.GetReqID(ctx))
+	}
+	s.updateFailedServices(services, s.procureServices(ctx, services, storedItems, report))
+	return s.saveReport(ctx, report, ctx.Err()), nil
+}
+
+func (s *Service) procureServices(ctx context.Context, services map[string]models.Service, storedItems map[string]models.StorageData, report *runReport) map[string]models.Service {
+	concurrency := s.procOpts.Concurrency
+	if concurrency <= 0 {
+		concurrency = max(len(services), 1)
+	}
+	sem := make(chan struct{}, concurrency)
+	failed := make(map[string]models.Service)
+	mu := sync.Mutex{}
 	wg := &sync.WaitGroup{}
-	for _, service := range services {
-		if err = ctx.Err(); err != nil {
+loop:
+	for id, service := range services {
+		if err := ctx.Err(); err != nil {
 			break
 		}
-		if len(service.ExtPaths) > 0 {
-			wg.Add(1)
-			go s.handleService(ctx, wg, service)
+		if service.DocOptions.Disabled || service.Denied || len(service.ExtPaths) == 0 {
+			var reason string
+			switch {
+			case service.DocOptions.Disabled:
+				logger.Debug("skipping disabled service", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, slog_attr.RequestIDKey, util.GetReqID(ctx))
+				reason = lib_models.ProcurementReasonDisabled
+			case service.Denied:
+				logger.Debug("skipping service denied by discovery rules", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, slog_attr.RequestIDKey, util.GetReqID(ctx))
+				reason = lib_models.ProcurementReasonDenied
+			}
+			report.addService(lib_models.ProcurementServiceResult{
+				ID:      service.ID,
+				Host:    service.Host,
+				Port:    service.Port,
+				Outcome: lib_models.ProcurementOutcomeFilteredOut,
+				Reason:  reason,
+				Docs:    getDeniedDocResults(service),
+			})
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break loop
 		}
+		wg.Add(1)
+		go func(id string, service models.Service) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			defer s.serviceLocks.Lock(id)()
+			result, err := s.handleService(ctx, service, storedItems)
+			result.Docs = append(result.Docs, getDeniedDocResults(service)...)
+			report.addService(result)
+			if err != nil && isRetryable(err) {
+				mu.Lock()
+				failed[id] = service
+				mu.Unlock()
+			}
+		}(id, service)
 	}
 	wg.Wait()
-	if err = s.cleanOldServices(ctx, services); err != nil {
-		logger.Error("removing old docs failed", attributes.ErrorKey, err, slog_attr.RequestIDKey, util.GetReqID(ctx))
+	return failed
+}
+
+func (s *Service) setFailedServices(services map[string]models.Service) {
+	s.failedMu.Lock()
+	defer s.failedMu.Unlock()
+	s.failed = services
+}
+
+func (s *Service) updateFailedServices(services, failed map[string]models.Service) {
+	s.failedMu.Lock()
+	defer s.failedMu.Unlock()
+	if s.failed == nil {
+		s.failed = make(map[string]models.Service)
 	}
-	return nil
+	for id := range services {
+		if service, ok := failed[id]; ok {
+			s.failed[id] = service
+		} else {
+			delete(s.failed, id)
+		}
+	}
+}
+
+func (s *Service) hasFailedServices() bool {
+	s.failedMu.RLock()
+	defer s.failedMu.RUnlock()
+	return len(s.failed) > 0
 }
 
-func (s *Service) cleanOldServices(ctx context.Context, services map[string]models.Service) error {
+func (s *Service) cleanOldServices(ctx context.Context, services map[string]models.Service, force bool) ([]string, error) {
 	storedServices, err := s.storageHdl.List(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	servicesSet := make(map[string]struct{})
 	for _, service := range services {
+		if service.DocOptions.Disabled {
+			continue
+		}
 		for _, extPath := range service.ExtPaths {
 			servicesSet[getStorageID(service.ID, extPath)] = struct{}{}
 		}
 	}
+	now := time.Now()
+	var oldIDs []string
 	for _, service := range storedServices {
-		if _, ok := servicesSet[service.ID]; !ok {
-			if err = s.storageHdl.Delete(ctx, service.ID); err != nil {
-				logger.Error("removing old doc failed", attributes.ErrorKey, err, slog_attr.RequestIDKey, util.GetReqID(ctx))
+		if _, ok := servicesSet[service.ID]; ok {
+			continue
+		}
+		service = getLatestData(service)
+		if s.procOpts.MissingGracePeriod > 0 {
+			val, _ := getArg(service.Args, missingSinceArgKey)
+			missingSince := parseTimeArg(val)
+			if missingSince == nil {
+				logger.Info("doc missing from discovery", slog_attr.IDKey, service.ID, slog_attr.RequestIDKey, util.GetReqID(ctx))
+				args := slices.DeleteFunc(slices.Clone(service.Args), func(arg [2]string) bool {
+					return arg[0] == missingSinceArgKey
+				})
+				if err = s.storageHdl.SetArgs(ctx, service.ID, append(args, [2]string{missingSinceArgKey, now.UTC().Format(time.RFC3339)})); err != nil {
+					logger.Error("marking old doc failed", slog_attr.IDKey, service.ID, attributes.ErrorKey, err, slog_attr.RequestIDKey, util.GetReqID(ctx))
+				}
+				continue
+			}
+			if now.Sub(*missingSince) < s.procOpts.MissingGracePeriod {
+				continue
 			}
 		}
+		oldIDs = append(oldIDs, service.ID)
 	}
-	return nil
+	if !force && exceedsDeletionLimit(len(oldIDs), len(storedServices), s.procOpts.MaxDeletionPercent) {
+		slices.Sort(oldIDs)
+		logger.Warn("skipping removal of old docs, deletion limit exceeded", slog_attr.NumberKey, len(oldIDs), slog_attr.TotalKey, len(storedServices), slog_attr.RequestIDKey, util.GetReqID(ctx))
+		return oldIDs, nil
+	}
+	for _, id := range oldIDs {
+		if err = s.storageHdl.Delete(ctx, id); err != nil {
+			logger.Error("removing old doc failed", attributes.ErrorKey, err, slog_attr.RequestIDKey, util.GetReqID(ctx))
+		}
+	}
+	return nil, nil
 }
 
-func (s *Service) handleService(ctx context.Context, wg *sync.WaitGroup, service models.Service) {
-	defer wg.Done()
-	ctxWt, cf := context.WithTimeout(ctx, s.timeout)
-	defer cf()
+func exceedsDeletionLimit(deletions, total, maxPercent int) bool {
+	if maxPercent <= 0 || total == 0 {
+		return false
+	}
+	return deletions*100 > total*maxPercent
+}
+
+func (s *Service) handleService(ctx context.Context, service models.Service, storedItems map[string]models.StorageData) (lib_models.ProcurementServiceResult, error) {
 	reqID := util.GetReqID(ctx)
-	logger.Debug("probing host", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, slog_attr.RequestIDKey, reqID)
-	doc, err := s.docClt.GetDoc(ctxWt, service.Protocol, service.Host, service.Port)
-	if err != nil {
-		logger.Debug("probing host failed", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, attributes.ErrorKey, err, slog_attr.RequestIDKey, reqID)
-		return
+	result := lib_models.ProcurementServiceResult{
+		ID:   service.ID,
+		Host: service.Host,
+		Port: service.Port,
 	}
-	var tmp map[string]json.RawMessage
-	if err := json.Unmarshal(doc, &tmp); err != nil {
-		logger.Error("unmarshalling doc failed", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, attributes.ErrorKey, err, slog_attr.RequestIDKey, reqID)
-		return
+	configHash, err := getConfigHash(service, s.apiGtwHost)
+	if err != nil {
+		logger.Error("generating config hash failed", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, attributes.ErrorKey, err, slog_attr.RequestIDKey, reqID)
 	}
-	if err = validateSwaggerKeys(tmp); err != nil {
-		logger.Error("validating keys failed", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, attributes.ErrorKey, err, slog_attr.RequestIDKey, reqID)
-		return
+	doc, tmp, docPath, err := s.probeService(ctx, service, getServiceState(service, configHash, storedItems))
+	result.DocPath = docPath
+	if err != nil {
+		if errors.Is(err, doc_clt.ErrNotModified) {
+			logger.Debug("doc not modified", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, slog_attr.DocPathKey, docPath, slog_attr.RequestIDKey, reqID)
+			result.Outcome = lib_models.ProcurementOutcomeUnchanged
+			s.updateServiceStatus(ctx, service, storedItems, "")
+			return result, nil
+		}
+		if ctx.Err() == nil {
+			s.updateServiceStatus(ctx, service, storedItems, err.Error())
+		}
+		result.Error = err.Error()
+		if isRetryable(err) {
+			result.Outcome = lib_models.ProcurementOutcomeUnreachable
+		} else {
+			result.Outcome = lib_models.ProcurementOutcomeInvalid
+		}
+		if isRetryable(err) && ctx.Err() == nil {
+			logger.Warn("fetching doc failed", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, attributes.ErrorKey, err, slog_attr.RequestIDKey, reqID)
+		} else {
+			logger.Debug("probing host failed", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, attributes.ErrorKey, err, slog_attr.RequestIDKey, reqID)
+		}
+		return result, err
 	}
 	sInfo, err := getSwaggerInfo(tmp)
 	if err != nil {
 		logger.Error("extracting info failed", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, attributes.ErrorKey, err, slog_attr.RequestIDKey, reqID)
-		return
+		s.updateServiceStatus(ctx, service, storedItems, err.Error())
+		return newInvalidResult(result, err), nil
 	}
 	sPaths, err := getSwaggerPaths(tmp)
 	if err != nil {
 		logger.Error("extracting paths failed", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, attributes.ErrorKey, err, slog_attr.RequestIDKey, reqID)
-		return
+		s.updateServiceStatus(ctx, service, storedItems, err.Error())
+		return newInvalidResult(result, err), nil
+	}
+	isV3 := isOpenApiV3(tmp)
+	if !isV3 {
+		if err = s.setSwaggerHostAndSchemes(tmp); err != nil {
+			logger.Error("setting swagger host and schemes failed", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, attributes.ErrorKey, err, slog_attr.RequestIDKey, reqID)
+			s.updateServiceStatus(ctx, service, storedItems, err.Error())
+			return newInvalidResult(result, err), nil
+		}
 	}
-	if err = s.setSwaggerHostAndSchemes(tmp); err != nil {
-		logger.Error("setting swagger host and schemes failed", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, attributes.ErrorKey, err, slog_attr.RequestIDKey, reqID)
-		return
+	sDoc := serviceDoc{
+		doc:        doc,
+		docPath:    docPath,
+		configHash: configHash,
+		data:       tmp,
+		info:       sInfo,
+		paths:      sPaths,
+		basePath:   getDocBasePath(tmp),
+		isV3:       isV3,
 	}
 	for _, extPath := range service.ExtPaths {
-		if err = s.setSwaggerBasePath(tmp, extPath); err != nil {
+		storageID := getStorageID(service.ID, extPath)
+		docResult := lib_models.ProcurementDocResult{
+			ID:       storageID,
+			BasePath: extPath,
+		}
+		docResult.Outcome, docResult.Error, docResult.Changes = s.handleServiceRoute(ctx, service, extPath, sDoc, storedItems[storageID])
+		if docResult.Outcome == lib_models.ProcurementOutcomeInvalid {
+			s.updateStatus(ctx, storedItems[storageID], docResult.Error)
+		}
+		result.Docs = append(result.Docs, docResult)
+	}
+	result.Outcome = getServiceOutcome(result.Docs)
+	return result, nil
+}
+
+func (s *Service) handleServiceRoute(ctx context.Context, service models.Service, extPath string, sDoc serviceDoc, storedItem models.StorageData) (string, string, *lib_models.DocChangeSummary) {
+	reqID := util.GetReqID(ctx)
+	extRoute := getServiceRoute(service, extPath)
+	if !routeMatchesHost(extRoute, s.apiGtwHost) {
+		logger.Debug("skipping route not exposed on api gateway host", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, slog_attr.BasePathKey, extPath, slog_attr.RequestIDKey, reqID)
+		return lib_models.ProcurementOutcomeFilteredOut, "", nil
+	}
+	rPaths, changed := getRoutePaths(sDoc.paths, extRoute, extPath, sDoc.basePath)
+	if len(sDoc.paths) > 0 && len(rPaths) == 0 {
+		logger.Debug("skipping route without exposed operations", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, slog_attr.BasePathKey, extPath, slog_attr.RequestIDKey, reqID)
+		return lib_models.ProcurementOutcomeFilteredOut, "", nil
+	}
+	rDoc := maps.Clone(sDoc.data)
+	if changed {
+		if err := setRoutePaths(rDoc, rPaths); err != nil {
+			logger.Error("setting route paths failed", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, slog_attr.BasePathKey, extPath, attributes.ErrorKey, err.Error(), slog_attr.RequestIDKey, reqID)
+			return lib_models.ProcurementOutcomeInvalid, err.Error(), nil
+		}
+	}
+	basePath := getRouteBasePath(extRoute, extPath, sDoc.basePath)
+	if sDoc.isV3 {
+		if err := s.setOpenApiServers(rDoc, basePath); err != nil {
+			logger.Error("setting openapi servers failed", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, slog_attr.BasePathKey, extPath, attributes.ErrorKey, err.Error(), slog_attr.RequestIDKey, reqID)
+			return lib_models.ProcurementOutcomeInvalid, err.Error(), nil
+		}
+	} else {
+		if err := s.setSwaggerBasePath(rDoc, basePath); err != nil {
 			logger.Error("setting swagger base path failed", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, slog_attr.BasePathKey, extPath, attributes.ErrorKey, err.Error(), slog_attr.RequestIDKey, reqID)
-			continue
+			return lib_models.ProcurementOutcomeInvalid, err.Error(), nil
+		}
+	}
+	b, err := json.Marshal(rDoc)
+	if err != nil {
+		logger.Error("marshaling doc failed", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, slog_attr.BasePathKey, extPath, attributes.ErrorKey, err.Error(), slog_attr.RequestIDKey, reqID)
+		return lib_models.ProcurementOutcomeInvalid, err.Error(), nil
+	}
+	args := [][2]string{
+		{titleArgKey, sDoc.info.Title},
+		{versionArgKey, sDoc.info.Version},
+		{descriptionArgKey, sDoc.info.Description},
+		{basePathArgKey, extPath},
+		{formatArgKey, sDoc.doc.Format},
+		{docPathArgKey, sDoc.docPath},
+	}
+	if service.Workspace != "" {
+		args = append(args, [2]string{workspaceArgKey, service.Workspace})
+	}
+	for _, route := range newRoutes(rPaths, basePath) {
+		args = append(args, [2]string{routeArgKey, route})
+	}
+	if sDoc.doc.Validators.ETag != "" {
+		args = append(args, [2]string{etagArgKey, sDoc.doc.Validators.ETag})
+	}
+	if sDoc.doc.Validators.LastModified != "" {
+		args = append(args, [2]string{lastModifiedArgKey, sDoc.doc.Validators.LastModified})
+	}
+	if sDoc.configHash != "" {
+		args = append(args, [2]string{configHashArgKey, sDoc.configHash})
+	}
+	args = append(args, [2]string{hashArgKey, getHash(b)})
+	if storedItem.ID != "" && slices.Equal(removeValidatorArgs(removeStatusArgs(storedItem.Args)), removeValidatorArgs(args)) {
+		logger.Debug("doc unchanged", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, slog_attr.BasePathKey, extPath, slog_attr.RequestIDKey, reqID)
+		s.updateStatus(ctx, models.StorageData{ID: storedItem.ID, Args: args}, "")
+		return lib_models.ProcurementOutcomeUnchanged, "", nil
+	}
+	var changes *lib_models.DocChangeSummary
+	if storedItem.ID != "" {
+		changes = s.diffStoredDoc(ctx, storedItem.ID, b)
+	}
+	if err = s.storageHdl.Write(ctx, getStorageID(service.ID, extPath), setStatusArgs(args, time.Now(), ""), b); err != nil {
+		logger.Error("writing doc failed", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, slog_attr.BasePathKey, extPath, attributes.ErrorKey, err, slog_attr.RequestIDKey, reqID)
+		return lib_models.ProcurementOutcomeFailed, err.Error(), nil
+	}
+	return lib_models.ProcurementOutcomeFetched, "", changes
+}
+
+func (s *Service) diffStoredDoc(ctx context.Context, id string, doc []byte) *lib_models.DocChangeSummary {
+	reqID := util.GetReqID(ctx)
+	revisions, err := s.storageHdl.ListRevisions(ctx, id)
+	if err != nil {
+		logger.Warn("listing doc revisions failed", slog_attr.IDKey, id, attributes.ErrorKey, err.Error(), slog_attr.RequestIDKey, reqID)
+		return nil
+	}
+	if len(revisions) == 0 {
+		return nil
+	}
+	storedDoc, err := s.storageHdl.ReadRevision(ctx, id, revisions[0].ID)
+	if err != nil {
+		logger.Warn("reading stored doc failed", slog_attr.IDKey, id, attributes.ErrorKey, err.Error(), slog_attr.RequestIDKey, reqID)
+		return nil
+	}
+	changes, err := doc_diff.Diff(storedDoc, doc)
+	if err != nil {
+		logger.Warn("diffing doc failed", slog_attr.IDKey, id, attributes.ErrorKey, err.Error(), slog_attr.RequestIDKey, reqID)
+		return nil
+	}
+	summary := doc_diff.Summarize(changes)
+	if summary.Breaking > 0 {
+		logger.Warn("doc has breaking changes", slog_attr.IDKey, id, slog_attr.ChangesKey, summary.Breaking, slog_attr.RequestIDKey, reqID)
+	}
+	return &summary
+}
+
+func (s *Service) probeService(ctx context.Context, service models.Service, state serviceState) (doc_clt.Doc, map[string]json.RawMessage, string, error) {
+	reqID := util.GetReqID(ctx)
+	docPaths := s.procOpts.DocPaths
+	if service.DocOptions.Path != "" {
+		docPaths = []string{service.DocOptions.Path}
+	}
+	var errs []error
+	for _, docPath := range getDocPaths(docPaths, state.docPath) {
+		if err := ctx.Err(); err != nil {
+			return doc_clt.Doc{}, nil, "", err
+		}
+		logger.Debug("probing host", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, slog_attr.DocPathKey, docPath, slog_attr.RequestIDKey, reqID)
+		var validators doc_clt.Validators
+		if docPath == state.docPath {
+			validators = state.validators
+		}
+		doc, tmp, err := s.getDoc(ctx, service, docPath, validators)
+		if errors.Is(err, doc_clt.ErrNotModified) {
+			return doc_clt.Doc{}, nil, docPath, err
 		}
-		b, err := json.Marshal(tmp)
 		if err != nil {
-			logger.Error("marshaling doc failed", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, slog_attr.BasePathKey, extPath, attributes.ErrorKey, err.Error(), slog_attr.RequestIDKey, reqID)
+			errs = append(errs, fmt.Errorf("%s: %w", docPath, err))
+			if isRetryable(err) {
+				break
+			}
+			continue
+		}
+		return doc, tmp, docPath, nil
+	}
+	if len(errs) == 0 {
+		return doc_clt.Doc{}, nil, "", errors.New("no doc paths")
+	}
+	return doc_clt.Doc{}, nil, "", errors.Join(errs...)
+}
+
+func (s *Service) getDoc(ctx context.Context, service models.Service, docPath string, validators doc_clt.Validators) (doc_clt.Doc, map[string]json.RawMessage, error) {
+	timeout := s.timeout
+	if service.DocOptions.Timeout > 0 {
+		timeout = service.DocOptions.Timeout
+	}
+	protocol := service.Protocol
+	if service.DocOptions.Protocol != "" {
+		protocol = service.DocOptions.Protocol
+	}
+	doc, err := s.fetchDoc(ctx, protocol, service.Host, service.Port, docPath, validators, timeout)
+	if err != nil {
+		return doc_clt.Doc{}, nil, err
+	}
+	var tmp map[string]json.RawMessage
+	if err = json.Unmarshal(doc.Data, &tmp); err != nil {
+		return doc_clt.Doc{}, nil, err
+	}
+	if err = validateSwaggerKeys(tmp); err != nil {
+		return doc_clt.Doc{}, nil, err
+	}
+	return doc, tmp, nil
+}
+
+func (s *Service) getStoredItems(ctx context.Context) (map[string]models.StorageData, error) {
+	storedServices, err := s.storageHdl.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items := make(map[string]models.StorageData)
+	for _, service := range storedServices {
+		items[service.ID] = getLatestData(service)
+	}
+	return items, nil
+}
+
+func getLatestData(sd models.StorageData) models.StorageData {
+	if sd.LatestArgs != nil {
+		sd.Args = sd.LatestArgs
+		sd.LatestArgs = nil
+	}
+	return sd
+}
+
+func getServiceState(service models.Service, configHash string, storedItems map[string]models.StorageData) serviceState {
+	var state serviceState
+	for _, extPath := range service.ExtPaths {
+		item, ok := storedItems[getStorageID(service.ID, extPath)]
+		if !ok {
 			continue
 		}
-		args := [][2]string{
-			{titleArgKey, sInfo.Title},
-			{versionArgKey, sInfo.Version},
-			{descriptionArgKey, sInfo.Description},
-			{basePathArgKey, extPath},
+		docPath, ok := getArg(item.Args, docPathArgKey)
+		if !ok {
+			continue
 		}
-		for _, route := range newRoutes(sPaths, extPath) {
-			args = append(args, [2]string{routeArgKey, route})
+		if state.docPath == "" {
+			state.docPath = docPath
 		}
-		if err = s.storageHdl.Write(ctx, getStorageID(service.ID, extPath), args, b); err != nil {
-			logger.Error("writing doc failed", slog_attr.HostKey, service.Host, slog_attr.PortKey, service.Port, slog_attr.BasePathKey, extPath, attributes.ErrorKey, err, slog_attr.RequestIDKey, reqID)
+		if docPath != state.docPath || configHash == "" {
 			continue
 		}
+		if hash, _ := getArg(item.Args, configHashArgKey); hash == configHash {
+			state.validators.ETag, _ = getArg(item.Args, etagArgKey)
+			state.validators.LastModified, _ = getArg(item.Args, lastModifiedArgKey)
+			return state
+		}
 	}
+	return state
+}
+
+func removeValidatorArgs(args [][2]string) [][2]string {
+	var newArgs [][2]string
+	for _, arg := range args {
+		if arg[0] != etagArgKey && arg[0] != lastModifiedArgKey {
+			newArgs = append(newArgs, arg)
+		}
+	}
+	return newArgs
+}
+
+func getArg(args [][2]string, key string) (string, bool) {
+	for _, arg := range args {
+		if arg[0] == key {
+			return arg[1], true
+		}
+	}
+	return "", false
+}
+
+func getDocPaths(docPaths []string, lastDocPath string) []string {
+	var paths []string
+	if lastDocPath != "" && slices.Contains(docPaths, lastDocPath) {
+		paths = append(paths, lastDocPath)
+	}
+	for _, docPath := range docPaths {
+		if !slices.Contains(paths, docPath) {
+			paths = append(paths, docPath)
+		}
+	}
+	return paths
 }
 
 func validateSwaggerKeys(tmp map[string]json.RawMessage) error {
@@ -187,6 +668,10 @@ func validateSwaggerKeys(tmp map[string]json.RawMessage) error {
 	return nil
 }
 
+func isOpenApiV3(tmp map[string]json.RawMessage) bool {
+	return srv_util.CheckForKeys(tmp, swaggerV3Keys)
+}
+
 func getSwaggerInfo(tmp map[string]json.RawMessage) (swaggerInfo, error) {
 	raw, ok := tmp[swaggerInfoKey]
 	if !ok {
@@ -206,7 +691,7 @@ func (s *Service) setSwaggerHostAndSchemes(tmp map[string]json.RawMessage) error
 	}
 	tmp[swaggerHostKey] = b
 	if _, ok := tmp[swaggerSchemesKey]; !ok {
-		b, err = json.Marshal([]string{"https"})
+		b, err = json.Marshal([]string{defaultScheme})
 		if err != nil {
 			return err
 		}
@@ -224,6 +709,57 @@ func (s *Service) setSwaggerBasePath(tmp map[string]json.RawMessage, basePath st
 	return nil
 }
 
+func (s *Service) setOpenApiServers(tmp map[string]json.RawMessage, basePath string) error {
+	u := url.URL{
+		Scheme: defaultScheme,
+		Host:   s.apiGtwHost,
+		Path:   basePath,
+	}
+	b, err := json.Marshal([]openApiServer{{URL: u.String()}})
+	if err != nil {
+		return err
+	}
+	tmp[swaggerServersKey] = b
+	return removePathServers(tmp)
+}
+
+func removePathServers(doc map[string]json.RawMessage) error {
+	paths, err := getSwaggerPaths(doc)
+	if err != nil {
+		return err
+	}
+	changed := false
+	for pth, pathItem := range paths {
+		if _, ok := pathItem[swaggerServersKey]; ok {
+			delete(pathItem, swaggerServersKey)
+			changed = true
+		}
+		for _, method := range httpMethods {
+			rawOperation, ok := pathItem[method]
+			if !ok {
+				continue
+			}
+			var operation map[string]json.RawMessage
+			if err = json.Unmarshal(rawOperation, &operation); err != nil {
+				return err
+			}
+			if _, ok = operation[swaggerServersKey]; !ok {
+				continue
+			}
+			delete(operation, swaggerServersKey)
+			if pathItem[method], err = json.Marshal(operation); err != nil {
+				return err
+			}
+			changed = true
+		}
+		paths[pth] = pathItem
+	}
+	if !changed {
+		return nil
+	}
+	return setDocPaths(doc, paths)
+}
+
 func newRoutes(pathsMap map[string]map[string]json.RawMessage, basePath string) []string {
 	var routes []string
 	for pth, obj := range pathsMap {
@@ -235,6 +771,18 @@ func newRoutes(pathsMap map[string]map[string]json.RawMessage, basePath string)
 	return routes
 }
 
+func findServices(services map[string]models.Service, ref string) map[string]models.Service {
+	matched := make(map[string]models.Service)
+	for id, service := range services {
+		if id == ref || strings.EqualFold(service.Host, ref) || slices.ContainsFunc(service.ExtPaths, func(extPath string) bool {
+			return getStorageID(service.ID, extPath) == ref
+		}) {
+			matched[id] = service
+		}
+	}
+	return matched
+}
+
 func getStorageID(srvID, extPath string) string {
 	return srvID + strings.Replace(extPath, "/", "_", -1)
 }
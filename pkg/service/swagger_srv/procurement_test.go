@@ -21,13 +21,17 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	lib_models "github.com/SENERGY-Platform/api-docs-provider/lib/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/components/doc_clt"
 	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
 	"github.com/SENERGY-Platform/api-docs-provider/pkg/util"
 	"github.com/SENERGY-Platform/go-service-base/struct-logger"
 	"os"
 	"reflect"
+	"slices"
 	"sync"
 	"testing"
+	"time"
 )
 
 func TestHandler_RefreshStorage(t *testing.T) {
@@ -58,9 +62,9 @@ func TestHandler_RefreshStorage(t *testing.T) {
 	}
 	docClt := &docCltMock{
 		Docs: map[string][]byte{
-			"ph0": validDoc,
-			"ph1": validDoc,
-			"ph2": []byte("test"),
+			"ph0/doc": validDoc,
+			"ph1/doc": validDoc,
+			"ph2/doc": []byte("test"),
 		},
 	}
 	discoveryHdl := &discoveryHdlMock{
@@ -73,11 +77,12 @@ func TestHandler_RefreshStorage(t *testing.T) {
 				ExtPaths: []string{"/t", "/d"},
 			},
 			"ph1": {
-				ID:       "ph1",
-				Host:     "h",
-				Port:     1,
-				Protocol: "p",
-				ExtPaths: []string{"/t"},
+				ID:        "ph1",
+				Host:      "h",
+				Port:      1,
+				Protocol:  "p",
+				Workspace: "ws",
+				ExtPaths:  []string{"/t"},
 			},
 			"ph2": {
 				ID:       "ph2",
@@ -103,91 +108,1000 @@ func TestHandler_RefreshStorage(t *testing.T) {
 	}
 	util.InitLogger(struct_logger.Config{}, os.Stderr, "", "")
 	InitLogger()
-	srv := New(storageHdl, discoveryHdl, docClt, nil, 0, "test.test", "")
-	err = srv.SwaggerRefreshDocs(context.Background())
+	srv := New(context.Background(), storageHdl, discoveryHdl, docClt, nil, &reportHdlMock{}, 0, "test.test", "", ProcurementOptions{DocPaths: []string{"/doc"}})
+	err = srv.SwaggerRefreshDocs(context.Background(), false)
+	if err != nil {
+		t.Error(err)
+	}
+	a := map[string]struct {
+		models.StorageData
+		data []byte
+	}{
+		"ph0_t": {
+			StorageData: models.StorageData{
+				ID: "ph0_t",
+				Args: [][2]string{
+					{titleArgKey, "Test"},
+					{versionArgKey, "v1"},
+					{descriptionArgKey, "Test Swagger"},
+					{basePathArgKey, "/t"},
+					{formatArgKey, models.DocFormatJSON},
+					{docPathArgKey, "/doc"},
+					{routeArgKey, fmt.Sprintf("/t/a%sget", routeDelimiter)},
+					{routeArgKey, fmt.Sprintf("/t/a%spost", routeDelimiter)},
+					{routeArgKey, fmt.Sprintf("/t/b%sget", routeDelimiter)},
+				},
+			},
+			data: validDoc,
+		},
+		"ph0_d": {
+			StorageData: models.StorageData{
+				ID: "ph0_d",
+				Args: [][2]string{
+					{titleArgKey, "Test"},
+					{versionArgKey, "v1"},
+					{descriptionArgKey, "Test Swagger"},
+					{basePathArgKey, "/d"},
+					{formatArgKey, models.DocFormatJSON},
+					{docPathArgKey, "/doc"},
+					{routeArgKey, fmt.Sprintf("/d/a%sget", routeDelimiter)},
+					{routeArgKey, fmt.Sprintf("/d/a%spost", routeDelimiter)},
+					{routeArgKey, fmt.Sprintf("/d/b%sget", routeDelimiter)},
+				},
+			},
+			data: validDoc,
+		},
+		"ph1_t": {
+			StorageData: models.StorageData{
+				ID: "ph1_t",
+				Args: [][2]string{
+					{titleArgKey, "Test"},
+					{versionArgKey, "v1"},
+					{descriptionArgKey, "Test Swagger"},
+					{basePathArgKey, "/t"},
+					{formatArgKey, models.DocFormatJSON},
+					{docPathArgKey, "/doc"},
+					{workspaceArgKey, "ws"},
+					{routeArgKey, fmt.Sprintf("/t/a%sget", routeDelimiter)},
+					{routeArgKey, fmt.Sprintf("/t/a%spost", routeDelimiter)},
+					{routeArgKey, fmt.Sprintf("/t/b%sget", routeDelimiter)},
+				},
+			},
+			data: validDoc,
+		},
+	}
+	if len(a) != len(storageHdl.Items) {
+		t.Errorf("expected %d items, got %d", len(a), len(storageHdl.Items))
+	}
+	for key, aItem := range a {
+		bItem, ok := storageHdl.Items[key]
+		if !ok {
+			t.Errorf("expected item %s not found", key)
+		}
+		if !reflect.DeepEqual(aItem.Args, removeArgs(bItem.Args, hashArgKey, configHashArgKey, lastFetchedArgKey, lastAttemptArgKey)) {
+			t.Errorf("expected %v, got %v", aItem.StorageData, bItem.StorageData)
+		}
+		var tmp map[string]json.RawMessage
+		if err = json.Unmarshal(bItem.data, &tmp); err != nil {
+			t.Fatal(err)
+		}
+		var bp string
+		if err = json.Unmarshal(tmp[swaggerBasePathKey], &bp); err != nil {
+			t.Fatal(err)
+		}
+		if bp != "/t" && bp != "/d" {
+			t.Errorf("expected /t or /d, got %s", bp)
+		}
+		var ah string
+		if err = json.Unmarshal(tmp[swaggerHostKey], &ah); err != nil {
+			t.Fatal(err)
+		}
+		if ah != "test.test" {
+			t.Errorf("expected test.test, got %s", ah)
+		}
+	}
+}
+
+func TestHandler_RefreshStorageOpenApi(t *testing.T) {
+	validDoc, err := os.ReadFile("test/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err = json.Unmarshal(validDoc, &doc); err != nil {
+		t.Fatal(err)
+	}
+	pathItem := doc[swaggerPathsKey].(map[string]any)["/a"].(map[string]any)
+	pathItem[swaggerServersKey] = []any{map[string]any{"url": "http://internal:8080/a"}}
+	pathItem["get"].(map[string]any)[swaggerServersKey] = []any{map[string]any{"url": "http://internal:8080/a/get"}}
+	if validDoc, err = json.Marshal(doc); err != nil {
+		t.Fatal(err)
+	}
+	storageHdl := &storageHdlMock{
+		Items: map[string]struct {
+			models.StorageData
+			data []byte
+		}{},
+	}
+	docClt := &docCltMock{
+		Docs: map[string][]byte{
+			"ph0/doc": validDoc,
+		},
+	}
+	discoveryHdl := &discoveryHdlMock{
+		Services: map[string]models.Service{
+			"ph0": {
+				ID:       "ph0",
+				Host:     "h",
+				Port:     0,
+				Protocol: "p",
+				ExtPaths: []string{"/t", "/d"},
+			},
+		},
+	}
+	util.InitLogger(struct_logger.Config{}, os.Stderr, "", "")
+	InitLogger()
+	srv := New(context.Background(), storageHdl, discoveryHdl, docClt, nil, &reportHdlMock{}, 0, "test.test", "", ProcurementOptions{DocPaths: []string{"/doc"}})
+	err = srv.SwaggerRefreshDocs(context.Background(), false)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(storageHdl.Items) != 2 {
+		t.Errorf("expected 2 items, got %d", len(storageHdl.Items))
+	}
+	for key, a := range map[string]string{
+		"ph0_t": "https://test.test/t",
+		"ph0_d": "https://test.test/d",
+	} {
+		item, ok := storageHdl.Items[key]
+		if !ok {
+			t.Errorf("expected item %s not found", key)
+			continue
+		}
+		var tmp map[string]json.RawMessage
+		if err = json.Unmarshal(item.data, &tmp); err != nil {
+			t.Fatal(err)
+		}
+		for _, k := range []string{swaggerHostKey, swaggerBasePathKey, swaggerSchemesKey} {
+			if _, ok = tmp[k]; ok {
+				t.Errorf("unexpected key '%s'", k)
+			}
+		}
+		var servers []openApiServer
+		if err = json.Unmarshal(tmp[swaggerServersKey], &servers); err != nil {
+			t.Fatal(err)
+		}
+		if len(servers) != 1 {
+			t.Fatalf("expected 1 server, got %d", len(servers))
+		}
+		if servers[0].URL != a {
+			t.Errorf("expected %s, got %s", a, servers[0].URL)
+		}
+		paths, err := getSwaggerPaths(tmp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok = paths["/a"][swaggerServersKey]; ok {
+			t.Errorf("unexpected path servers in %s", key)
+		}
+		var operation map[string]json.RawMessage
+		if err = json.Unmarshal(paths["/a"]["get"], &operation); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok = operation[swaggerServersKey]; ok {
+			t.Errorf("unexpected operation servers in %s", key)
+		}
+	}
+}
+
+func TestHandler_RefreshStorageDocPaths(t *testing.T) {
+	validDoc, err := os.ReadFile("test/swagger.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	storageHdl := &storageHdlMock{
+		Items: map[string]struct {
+			models.StorageData
+			data []byte
+		}{
+			"ph1_t": {
+				StorageData: models.StorageData{
+					ID:   "ph1_t",
+					Args: [][2]string{{basePathArgKey, "/t"}, {docPathArgKey, "/openapi.json"}},
+				},
+				data: validDoc,
+			},
+		},
+	}
+	docClt := &docCltMock{
+		Docs: map[string][]byte{
+			"ph0/doc":          []byte(`{"status":"ok"}`),
+			"ph0/v3/api-docs":  validDoc,
+			"ph0/openapi.json": validDoc,
+			"ph1/doc":          validDoc,
+			"ph1/openapi.json": validDoc,
+		},
+	}
+	discoveryHdl := &discoveryHdlMock{
+		Services: map[string]models.Service{
+			"ph0": {
+				ID:       "ph0",
+				Host:     "h",
+				Port:     0,
+				Protocol: "p",
+				ExtPaths: []string{"/t"},
+			},
+			"ph1": {
+				ID:       "ph1",
+				Host:     "h",
+				Port:     1,
+				Protocol: "p",
+				ExtPaths: []string{"/t"},
+			},
+		},
+	}
+	util.InitLogger(struct_logger.Config{}, os.Stderr, "", "")
+	InitLogger()
+	srv := New(context.Background(), storageHdl, discoveryHdl, docClt, nil, &reportHdlMock{}, 0, "test.test", "", ProcurementOptions{DocPaths: []string{"/doc", "/v3/api-docs", "/openapi.json"}})
+	err = srv.SwaggerRefreshDocs(context.Background(), false)
+	if err != nil {
+		t.Error(err)
+	}
+	for key, a := range map[string]string{
+		"ph0_t": "/v3/api-docs",
+		"ph1_t": "/openapi.json",
+	} {
+		item, ok := storageHdl.Items[key]
+		if !ok {
+			t.Errorf("expected item %s not found", key)
+			continue
+		}
+		var docPath string
+		for _, arg := range item.Args {
+			if arg[0] == docPathArgKey {
+				docPath = arg[1]
+			}
+		}
+		if docPath != a {
+			t.Errorf("expected %s, got %s", a, docPath)
+		}
+	}
+	a := []string{"ph0/doc", "ph0/v3/api-docs", "ph1/openapi.json"}
+	b := docClt.Calls
+	slices.Sort(b)
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("expected %v, got %v", a, b)
+	}
+}
+
+func TestHandler_RefreshStorageDocOptions(t *testing.T) {
+	validDoc, err := os.ReadFile("test/swagger.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	storageHdl := &storageHdlMock{
+		Items: map[string]struct {
+			models.StorageData
+			data []byte
+		}{
+			"ph1_t": {
+				StorageData: models.StorageData{
+					ID:   "ph1_t",
+					Args: [][2]string{{basePathArgKey, "/t"}},
+				},
+				data: validDoc,
+			},
+		},
+	}
+	docClt := &docCltMock{
+		Docs: map[string][]byte{
+			"ph0/doc":          validDoc,
+			"sh0/openapi.json": validDoc,
+			"ph1/doc":          validDoc,
+		},
+	}
+	discoveryHdl := &discoveryHdlMock{
+		Services: map[string]models.Service{
+			"ph0": {
+				ID:       "ph0",
+				Host:     "h",
+				Port:     0,
+				Protocol: "p",
+				ExtPaths: []string{"/t"},
+				DocOptions: models.DocOptions{
+					Path:     "/openapi.json",
+					Protocol: "s",
+				},
+			},
+			"ph1": {
+				ID:       "ph1",
+				Host:     "h",
+				Port:     1,
+				Protocol: "p",
+				ExtPaths: []string{"/t"},
+				DocOptions: models.DocOptions{
+					Disabled: true,
+				},
+			},
+		},
+	}
+	util.InitLogger(struct_logger.Config{}, os.Stderr, "", "")
+	InitLogger()
+	srv := New(context.Background(), storageHdl, discoveryHdl, docClt, nil, &reportHdlMock{}, 0, "test.test", "", ProcurementOptions{DocPaths: []string{"/doc"}})
+	err = srv.SwaggerRefreshDocs(context.Background(), false)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(storageHdl.Items) != 1 {
+		t.Errorf("expected 1 item, got %d", len(storageHdl.Items))
+	}
+	if _, ok := storageHdl.Items["ph0_t"]; !ok {
+		t.Error("expected 'ph0_t'")
+	}
+	a := []string{"sh0/openapi.json"}
+	if !reflect.DeepEqual(a, docClt.Calls) {
+		t.Errorf("expected %v, got %v", a, docClt.Calls)
+	}
+}
+
+func TestHandler_RefreshStorageRoutes(t *testing.T) {
+	validDoc, err := os.ReadFile("test/swagger.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tmp map[string]json.RawMessage
+	if err = json.Unmarshal(validDoc, &tmp); err != nil {
+		t.Fatal(err)
+	}
+	tmp[swaggerPathsKey] = json.RawMessage(`{"/x/a":{"get":{"responses":{"200":{"description":"ok"}}}},"/y":{"get":{"responses":{"200":{"description":"ok"}}}}}`)
+	tmp[swaggerBasePathKey] = json.RawMessage(`"/"`)
+	prefixedDoc, err := json.Marshal(tmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmp[swaggerBasePathKey] = json.RawMessage(`"/api"`)
+	basePathDoc, err := json.Marshal(tmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storageHdl := &storageHdlMock{
+		Items: map[string]struct {
+			models.StorageData
+			data []byte
+		}{},
+	}
+	docClt := &docCltMock{
+		Docs: map[string][]byte{
+			"ph0/doc": validDoc,
+			"ph1/doc": prefixedDoc,
+			"ph2/doc": basePathDoc,
+		},
+	}
+	discoveryHdl := &discoveryHdlMock{
+		Services: map[string]models.Service{
+			"ph0": {
+				ID:       "ph0",
+				Host:     "h",
+				Port:     0,
+				Protocol: "p",
+				ExtPaths: []string{"/t", "/d", "/h"},
+				Routes: map[string]models.Route{
+					"/t": {Methods: []string{"GET"}, StripPath: true},
+					"/d": {Methods: []string{"DELETE"}, StripPath: true},
+					"/h": {Hosts: []string{"other.test"}, StripPath: true},
+				},
+			},
+			"ph1": {
+				ID:       "ph1",
+				Host:     "h",
+				Port:     1,
+				Protocol: "p",
+				ExtPaths: []string{"/x"},
+				Routes: map[string]models.Route{
+					"/x": {Hosts: []string{"*.test"}},
+				},
+			},
+			"ph2": {
+				ID:       "ph2",
+				Host:     "h",
+				Port:     2,
+				Protocol: "p",
+				ExtPaths: []string{"/api/x"},
+				Routes: map[string]models.Route{
+					"/api/x": {},
+				},
+			},
+		},
+	}
+	util.InitLogger(struct_logger.Config{}, os.Stderr, "", "")
+	InitLogger()
+	srv := New(context.Background(), storageHdl, discoveryHdl, docClt, nil, &reportHdlMock{}, 0, "test.test", "", ProcurementOptions{DocPaths: []string{"/doc"}})
+	err = srv.SwaggerRefreshDocs(context.Background(), false)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(storageHdl.Items) != 3 {
+		t.Errorf("expected 3 items, got %d", len(storageHdl.Items))
+	}
+	t.Run("methods", func(t *testing.T) {
+		item, ok := storageHdl.Items["ph0_t"]
+		if !ok {
+			t.Fatal("expected 'ph0_t'")
+		}
+		routes, err := getRoutes(item.Args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a := map[string][]string{
+			"/t/a": {"get"},
+			"/t/b": {"get"},
+		}
+		if !reflect.DeepEqual(a, routes) {
+			t.Errorf("expected %v, got %v", a, routes)
+		}
+		var doc map[string]json.RawMessage
+		if err = json.Unmarshal(item.data, &doc); err != nil {
+			t.Fatal(err)
+		}
+		paths, err := getSwaggerPaths(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok = paths["/a"]["post"]; ok {
+			t.Error("unexpected post operation")
+		}
+		defs, err := getContainer(doc, []string{swaggerDefinitionsKey})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok = defs["B"]; ok {
+			t.Error("unexpected definition 'B'")
+		}
+	})
+	t.Run("strip path", func(t *testing.T) {
+		item, ok := storageHdl.Items["ph1_x"]
+		if !ok {
+			t.Fatal("expected 'ph1_x'")
+		}
+		routes, err := getRoutes(item.Args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a := map[string][]string{
+			"/x/a": {"get"},
+		}
+		if !reflect.DeepEqual(a, routes) {
+			t.Errorf("expected %v, got %v", a, routes)
+		}
+		var doc map[string]json.RawMessage
+		if err = json.Unmarshal(item.data, &doc); err != nil {
+			t.Fatal(err)
+		}
+		basePath, err := getBasePath(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if basePath != "/" {
+			t.Errorf("expected /, got %s", basePath)
+		}
+	})
+	t.Run("strip path with base path", func(t *testing.T) {
+		item, ok := storageHdl.Items["ph2_api_x"]
+		if !ok {
+			t.Fatal("expected 'ph2_api_x'")
+		}
+		routes, err := getRoutes(item.Args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a := map[string][]string{
+			"/api/x/a": {"get"},
+		}
+		if !reflect.DeepEqual(a, routes) {
+			t.Errorf("expected %v, got %v", a, routes)
+		}
+		var doc map[string]json.RawMessage
+		if err = json.Unmarshal(item.data, &doc); err != nil {
+			t.Fatal(err)
+		}
+		basePath, err := getBasePath(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if basePath != "/api" {
+			t.Errorf("expected /api, got %s", basePath)
+		}
+	})
+}
+
+func TestHandler_RefreshStorageConcurrency(t *testing.T) {
+	validDoc, err := os.ReadFile("test/swagger.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	docClt := &docCltMock{
+		Docs:  make(map[string][]byte),
+		Delay: time.Millisecond * 20,
+	}
+	discoveryHdl := &discoveryHdlMock{
+		Services: make(map[string]models.Service),
+	}
+	for i := 0; i < 8; i++ {
+		id := fmt.Sprintf("ph%d", i)
+		docClt.Docs[id+"/doc"] = validDoc
+		discoveryHdl.Services[id] = models.Service{
+			ID:       id,
+			Host:     "h",
+			Port:     i,
+			Protocol: "p",
+			ExtPaths: []string{"/t"},
+		}
+	}
+	util.InitLogger(struct_logger.Config{}, os.Stderr, "", "")
+	InitLogger()
+	t.Run("limit", func(t *testing.T) {
+		storageHdl := &storageHdlMock{}
+		srv := New(context.Background(), storageHdl, discoveryHdl, docClt, nil, &reportHdlMock{}, time.Second, "test.test", "", ProcurementOptions{DocPaths: []string{"/doc"}, Concurrency: 2})
+		if err = srv.SwaggerRefreshDocs(context.Background(), false); err != nil {
+			t.Fatal(err)
+		}
+		if docClt.MaxActive > 2 {
+			t.Errorf("expected max 2 active requests, got %d", docClt.MaxActive)
+		}
+		if len(storageHdl.Items) != 8 {
+			t.Errorf("expected 8 items, got %d", len(storageHdl.Items))
+		}
+	})
+	t.Run("canceled", func(t *testing.T) {
+		docClt.Calls = nil
+		storageHdl := &storageHdlMock{}
+		srv := New(context.Background(), storageHdl, discoveryHdl, docClt, nil, &reportHdlMock{}, time.Second, "test.test", "", ProcurementOptions{DocPaths: []string{"/doc"}, Concurrency: 1})
+		ctx, cf := context.WithTimeout(context.Background(), time.Millisecond*30)
+		defer cf()
+		if err = srv.SwaggerRefreshDocs(ctx, false); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded, got %v", err)
+		}
+		if len(docClt.Calls) >= 8 {
+			t.Errorf("expected less than 8 calls, got %d", len(docClt.Calls))
+		}
+	})
+}
+
+func TestHandler_RefreshStorageUnchanged(t *testing.T) {
+	validDoc, err := os.ReadFile("test/swagger.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	storageHdl := &storageHdlMock{}
+	docClt := &docCltMock{
+		Docs: map[string][]byte{
+			"ph0/doc": validDoc,
+			"ph1/doc": validDoc,
+		},
+		Validators: map[string]doc_clt.Validators{
+			"ph0/doc": {ETag: "\"a\""},
+		},
+	}
+	discoveryHdl := &discoveryHdlMock{
+		Services: map[string]models.Service{
+			"ph0": {
+				ID:       "ph0",
+				Host:     "h",
+				Port:     0,
+				Protocol: "p",
+				ExtPaths: []string{"/t"},
+			},
+			"ph1": {
+				ID:       "ph1",
+				Host:     "h",
+				Port:     1,
+				Protocol: "p",
+				ExtPaths: []string{"/t"},
+			},
+		},
+	}
+	util.InitLogger(struct_logger.Config{}, os.Stderr, "", "")
+	InitLogger()
+	srv := New(context.Background(), storageHdl, discoveryHdl, docClt, nil, &reportHdlMock{}, 0, "test.test", "", ProcurementOptions{DocPaths: []string{"/doc"}})
+	if err = srv.SwaggerRefreshDocs(context.Background(), false); err != nil {
+		t.Fatal(err)
+	}
+	if storageHdl.Writes != 2 {
+		t.Fatalf("expected 2 writes, got %d", storageHdl.Writes)
+	}
+	if v, _ := getArg(storageHdl.Items["ph0_t"].Args, etagArgKey); v != "\"a\"" {
+		t.Errorf("expected etag arg, got '%s'", v)
+	}
+	if _, ok := getArg(storageHdl.Items["ph0_t"].Args, hashArgKey); !ok {
+		t.Error("expected hash arg")
+	}
+	t.Run("unchanged", func(t *testing.T) {
+		storageHdl.Writes = 0
+		if err = srv.SwaggerRefreshDocs(context.Background(), false); err != nil {
+			t.Fatal(err)
+		}
+		if storageHdl.Writes != 0 {
+			t.Errorf("expected 0 writes, got %d", storageHdl.Writes)
+		}
+	})
+	t.Run("validators changed", func(t *testing.T) {
+		storageHdl.Writes = 0
+		docClt.Validators["ph0/doc"] = doc_clt.Validators{ETag: "\"b\""}
+		if err = srv.SwaggerRefreshDocs(context.Background(), false); err != nil {
+			t.Fatal(err)
+		}
+		if storageHdl.Writes != 0 {
+			t.Errorf("expected 0 writes, got %d", storageHdl.Writes)
+		}
+		if v, _ := getArg(storageHdl.Items["ph0_t"].Args, etagArgKey); v != "\"b\"" {
+			t.Errorf("expected updated etag arg, got '%s'", v)
+		}
+		if _, ok := getArg(storageHdl.Items["ph0_t"].Args, lastFetchedArgKey); !ok {
+			t.Error("expected last fetched arg")
+		}
+	})
+	t.Run("route config changed", func(t *testing.T) {
+		storageHdl.Writes = 0
+		discoveryHdl.Services["ph0"] = models.Service{
+			ID:       "ph0",
+			Host:     "h",
+			Port:     0,
+			Protocol: "p",
+			ExtPaths: []string{"/t"},
+			Routes: map[string]models.Route{
+				"/t": {Methods: []string{"GET"}, StripPath: true},
+			},
+		}
+		if err = srv.SwaggerRefreshDocs(context.Background(), false); err != nil {
+			t.Fatal(err)
+		}
+		if storageHdl.Writes != 1 {
+			t.Errorf("expected 1 write, got %d", storageHdl.Writes)
+		}
+	})
+	t.Run("content changed", func(t *testing.T) {
+		storageHdl.Writes = 0
+		openApiDoc, err := os.ReadFile("test/openapi.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		docClt.Docs["ph1/doc"] = openApiDoc
+		if err = srv.SwaggerRefreshDocs(context.Background(), false); err != nil {
+			t.Fatal(err)
+		}
+		if storageHdl.Writes != 1 {
+			t.Errorf("expected 1 write, got %d", storageHdl.Writes)
+		}
+	})
+}
+
+func TestHandler_RefreshStorageChanges(t *testing.T) {
+	validDoc, err := os.ReadFile("test/swagger.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	storageHdl := &storageHdlMock{}
+	docClt := &docCltMock{
+		Docs: map[string][]byte{
+			"ph0/doc": validDoc,
+		},
+	}
+	discoveryHdl := &discoveryHdlMock{
+		Services: map[string]models.Service{
+			"ph0": {ID: "ph0", Host: "h", Port: 0, Protocol: "p", ExtPaths: []string{"/t"}},
+		},
+	}
+	util.InitLogger(struct_logger.Config{}, os.Stderr, "", "")
+	InitLogger()
+	reportHdl := &reportHdlMock{}
+	srv := New(context.Background(), storageHdl, discoveryHdl, docClt, nil, reportHdl, 0, "test.test", "", ProcurementOptions{DocPaths: []string{"/doc"}})
+	if err = srv.SwaggerRefreshDocs(context.Background(), false); err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err = json.Unmarshal(validDoc, &doc); err != nil {
+		t.Fatal(err)
+	}
+	doc["paths"].(map[string]any)["/z"] = map[string]any{"get": map[string]any{}}
+	if docClt.Docs["ph0/doc"], err = json.Marshal(doc); err != nil {
+		t.Fatal(err)
+	}
+	storageHdl.ReadErr = errors.New("test")
+	if err = srv.SwaggerRefreshDocs(context.Background(), false); err != nil {
+		t.Fatal(err)
+	}
+	if len(reportHdl.Runs) != 2 {
+		t.Fatalf("expected 2 runs, got %d", len(reportHdl.Runs))
+	}
+	a := &lib_models.DocChangeSummary{NonBreaking: 1}
+	if b := reportHdl.Runs[1].Services[0].Docs[0].Changes; !reflect.DeepEqual(a, b) {
+		t.Errorf("expected %v, got %v", a, b)
+	}
+}
+
+func TestHandler_RefreshStorageReport(t *testing.T) {
+	validDoc, err := os.ReadFile("test/swagger.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	docClt := &docCltMock{
+		Docs: map[string][]byte{
+			"ph0/doc": validDoc,
+			"ph2/doc": []byte("test"),
+			"ph4/doc": validDoc,
+			"ph6/doc": validDoc,
+		},
+		Errs: map[string]error{
+			"ph1/doc": doc_clt.NewResponseError(503, errors.New("unavailable")),
+		},
+	}
+	discoveryHdl := &discoveryHdlMock{
+		Services: map[string]models.Service{
+			"ph0": {ID: "ph0", Host: "h", Port: 0, Protocol: "p", ExtPaths: []string{"/t"}},
+			"ph1": {ID: "ph1", Host: "h", Port: 1, Protocol: "p", ExtPaths: []string{"/t"}},
+			"ph2": {ID: "ph2", Host: "h", Port: 2, Protocol: "p", ExtPaths: []string{"/t"}},
+			"ph3": {ID: "ph3", Host: "h", Port: 3, Protocol: "p", ExtPaths: []string{"/t"}, DocOptions: models.DocOptions{Disabled: true}},
+			"ph4": {ID: "ph4", Host: "h", Port: 4, Protocol: "p", ExtPaths: []string{"/t"}, Routes: map[string]models.Route{"/t": {Hosts: []string{"other"}, StripPath: true}}},
+			"ph5": {ID: "ph5", Host: "h", Port: 5, Protocol: "p", Denied: true, DeniedPaths: []string{"/t"}},
+			"ph6": {ID: "ph6", Host: "h", Port: 6, Protocol: "p", ExtPaths: []string{"/t"}, DeniedPaths: []string{"/x"}},
+		},
+	}
+	util.InitLogger(struct_logger.Config{}, os.Stderr, "", "")
+	InitLogger()
+	reportHdl := &reportHdlMock{}
+	srv := New(context.Background(), &storageHdlMock{}, discoveryHdl, docClt, nil, reportHdl, 0, "test.test", "", ProcurementOptions{DocPaths: []string{"/doc"}})
+	if err = srv.SwaggerRefreshDocs(context.Background(), false); err != nil {
+		t.Fatal(err)
+	}
+	if len(reportHdl.Runs) != 1 {
+		t.Fatalf("expected 1 run, got %d", len(reportHdl.Runs))
+	}
+	run := reportHdl.Runs[0]
+	if run.ID == "" || run.Type != lib_models.ProcurementRunTypeFull || run.Discovered != 7 || run.End.Before(run.Start) {
+		t.Errorf("unexpected run info %+v", run.ProcurementRunInfo)
+	}
+	a := map[string]int{
+		lib_models.ProcurementOutcomeFetched:     2,
+		lib_models.ProcurementOutcomeUnreachable: 1,
+		lib_models.ProcurementOutcomeInvalid:     1,
+		lib_models.ProcurementOutcomeFilteredOut: 3,
+	}
+	if !reflect.DeepEqual(a, run.Outcomes) {
+		t.Errorf("expected %v, got %v", a, run.Outcomes)
+	}
+	var ids []string
+	for _, result := range run.Services {
+		ids = append(ids, result.ID)
+	}
+	if !reflect.DeepEqual([]string{"ph0", "ph1", "ph2", "ph3", "ph4", "ph5", "ph6"}, ids) {
+		t.Errorf("unexpected services %v", ids)
+	}
+	aDocs := []lib_models.ProcurementDocResult{{ID: "ph0_t", BasePath: "/t", Outcome: lib_models.ProcurementOutcomeFetched}}
+	if !reflect.DeepEqual(aDocs, run.Services[0].Docs) || run.Services[0].DocPath != "/doc" {
+		t.Errorf("unexpected result %+v", run.Services[0])
+	}
+	if run.Services[1].Error == "" || run.Services[2].Error == "" {
+		t.Error("expected error text")
+	}
+	if run.Services[3].Reason != lib_models.ProcurementReasonDisabled {
+		t.Errorf("expected reason %s, got %s", lib_models.ProcurementReasonDisabled, run.Services[3].Reason)
+	}
+	aDocs = []lib_models.ProcurementDocResult{{ID: "ph5_t", BasePath: "/t", Outcome: lib_models.ProcurementOutcomeFilteredOut, Reason: lib_models.ProcurementReasonDenied}}
+	if r := run.Services[5]; r.Outcome != lib_models.ProcurementOutcomeFilteredOut || r.Reason != lib_models.ProcurementReasonDenied || !reflect.DeepEqual(aDocs, r.Docs) {
+		t.Errorf("unexpected result %+v", r)
+	}
+	aDocs = []lib_models.ProcurementDocResult{
+		{ID: "ph6_t", BasePath: "/t", Outcome: lib_models.ProcurementOutcomeFetched},
+		{ID: "ph6_x", BasePath: "/x", Outcome: lib_models.ProcurementOutcomeFilteredOut, Reason: lib_models.ProcurementReasonDenied},
+	}
+	if r := run.Services[6]; r.Outcome != lib_models.ProcurementOutcomeFetched || !reflect.DeepEqual(aDocs, r.Docs) {
+		t.Errorf("unexpected result %+v", r)
+	}
+	t.Run("unchanged", func(t *testing.T) {
+		if err = srv.SwaggerRefreshDocs(context.Background(), false); err != nil {
+			t.Fatal(err)
+		}
+		if o := reportHdl.Runs[1].Services[0].Outcome; o != lib_models.ProcurementOutcomeUnchanged {
+			t.Errorf("expected unchanged, got %s", o)
+		}
+	})
+	t.Run("follow-up", func(t *testing.T) {
+		delete(docClt.Errs, "ph1/doc")
+		docClt.Docs["ph1/doc"] = validDoc
+		if err = srv.refreshFailedServices(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		run := reportHdl.Runs[2]
+		if run.Type != lib_models.ProcurementRunTypeFollowUp || run.Discovered != 1 || run.Outcomes[lib_models.ProcurementOutcomeFetched] != 1 {
+			t.Errorf("unexpected run info %+v", run.ProcurementRunInfo)
+		}
+	})
+	t.Run("status", func(t *testing.T) {
+		status, err := srv.SwaggerGetProcurementStatus(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.Running || len(status.Runs) != 3 || status.Runs[0].ID != reportHdl.Runs[2].ID {
+			t.Errorf("unexpected status %+v", status)
+		}
+		b, err := srv.SwaggerGetProcurementRun(context.Background(), run.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.ID != run.ID {
+			t.Errorf("expected %s, got %s", run.ID, b.ID)
+		}
+	})
+	t.Run("discovery error", func(t *testing.T) {
+		discoveryHdl.Err = errors.New("test")
+		if err = srv.SwaggerRefreshDocs(context.Background(), false); err == nil {
+			t.Error("expected error")
+		}
+		if run := reportHdl.Runs[len(reportHdl.Runs)-1]; run.Error != "test" {
+			t.Errorf("expected error in report, got '%s'", run.Error)
+		}
+		discoveryHdl.Err = nil
+	})
+}
+
+func TestService_SwaggerRefreshService(t *testing.T) {
+	validDoc, err := os.ReadFile("test/swagger.json")
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
-	a := map[string]struct {
-		models.StorageData
-		data []byte
-	}{
-		"ph0_t": {
-			StorageData: models.StorageData{
-				ID: "ph0_t",
-				Args: [][2]string{
-					{titleArgKey, "Test"},
-					{versionArgKey, "v1"},
-					{descriptionArgKey, "Test Swagger"},
-					{basePathArgKey, "/t"},
-					{routeArgKey, fmt.Sprintf("/t/a%sget", routeDelimiter)},
-					{routeArgKey, fmt.Sprintf("/t/a%spost", routeDelimiter)},
-					{routeArgKey, fmt.Sprintf("/t/b%sget", routeDelimiter)},
-				},
-			},
-			data: validDoc,
-		},
-		"ph0_d": {
-			StorageData: models.StorageData{
-				ID: "ph0_d",
-				Args: [][2]string{
-					{titleArgKey, "Test"},
-					{versionArgKey, "v1"},
-					{descriptionArgKey, "Test Swagger"},
-					{basePathArgKey, "/d"},
-					{routeArgKey, fmt.Sprintf("/d/a%sget", routeDelimiter)},
-					{routeArgKey, fmt.Sprintf("/d/a%spost", routeDelimiter)},
-					{routeArgKey, fmt.Sprintf("/d/b%sget", routeDelimiter)},
-				},
-			},
-			data: validDoc,
-		},
-		"ph1_t": {
-			StorageData: models.StorageData{
-				ID: "ph1_t",
-				Args: [][2]string{
-					{titleArgKey, "Test"},
-					{versionArgKey, "v1"},
-					{descriptionArgKey, "Test Swagger"},
-					{basePathArgKey, "/t"},
-					{routeArgKey, fmt.Sprintf("/t/a%sget", routeDelimiter)},
-					{routeArgKey, fmt.Sprintf("/t/a%spost", routeDelimiter)},
-					{routeArgKey, fmt.Sprintf("/t/b%sget", routeDelimiter)},
-				},
-			},
-			data: validDoc,
+	docClt := &docCltMock{
+		Docs: map[string][]byte{
+			"ph00/doc": validDoc,
+			"ph10/doc": validDoc,
+			"ph11/doc": validDoc,
 		},
 	}
-	if len(a) != len(storageHdl.Items) {
-		t.Errorf("expected %d items, got %d", len(a), len(storageHdl.Items))
+	discoveryHdl := &discoveryHdlMock{
+		Services: map[string]models.Service{
+			"ph0": {ID: "ph0", Host: "h0", Port: 0, Protocol: "p", ExtPaths: []string{"/t"}},
+			"ph1": {ID: "ph1", Host: "h1", Port: 0, Protocol: "p", ExtPaths: []string{"/t"}},
+			"ph2": {ID: "ph2", Host: "h1", Port: 1, Protocol: "p", ExtPaths: []string{"/t"}},
+		},
 	}
-	for key, aItem := range a {
-		bItem, ok := storageHdl.Items[key]
-		if !ok {
-			t.Errorf("expected item %s not found", key)
+	util.InitLogger(struct_logger.Config{}, os.Stderr, "", "")
+	InitLogger()
+	storageHdl := &storageHdlMock{}
+	reportHdl := &reportHdlMock{}
+	srv := New(context.Background(), storageHdl, discoveryHdl, docClt, nil, reportHdl, 0, "test.test", "", ProcurementOptions{DocPaths: []string{"/doc"}})
+	t.Run("by id", func(t *testing.T) {
+		run, err := srv.SwaggerRefreshService(context.Background(), "ph0")
+		if err != nil {
+			t.Fatal(err)
 		}
-		if !reflect.DeepEqual(aItem.StorageData, bItem.StorageData) {
-			t.Errorf("expected %v, got %v", aItem.StorageData, bItem.StorageData)
+		if run.Type != lib_models.ProcurementRunTypeService || len(run.Services) != 1 || run.Services[0].Outcome != lib_models.ProcurementOutcomeFetched {
+			t.Errorf("unexpected run %+v", run)
 		}
-		var tmp map[string]json.RawMessage
-		if err = json.Unmarshal(bItem.data, &tmp); err != nil {
+		if _, ok := storageHdl.Items["ph0_t"]; !ok || len(storageHdl.Items) != 1 {
+			t.Errorf("unexpected items %v", storageHdl.Items)
+		}
+		if len(reportHdl.Runs) != 1 || reportHdl.Runs[0].ID != run.ID {
+			t.Error("expected saved report")
+		}
+	})
+	t.Run("by storage id", func(t *testing.T) {
+		run, err := srv.SwaggerRefreshService(context.Background(), "ph0_t")
+		if err != nil {
 			t.Fatal(err)
 		}
-		var bp string
-		if err = json.Unmarshal(tmp[swaggerBasePathKey], &bp); err != nil {
+		if len(run.Services) != 1 || run.Services[0].ID != "ph0" || run.Services[0].Outcome != lib_models.ProcurementOutcomeUnchanged {
+			t.Errorf("unexpected run %+v", run)
+		}
+	})
+	t.Run("by host", func(t *testing.T) {
+		docClt.Errs = map[string]error{"ph11/doc": doc_clt.NewResponseError(503, errors.New("unavailable"))}
+		run, err := srv.SwaggerRefreshService(context.Background(), "H1")
+		if err != nil {
 			t.Fatal(err)
 		}
-		if bp != "/t" && bp != "/d" {
-			t.Errorf("expected /t or /d, got %s", bp)
+		if len(run.Services) != 2 || run.Services[0].ID != "ph1" || run.Services[1].ID != "ph2" {
+			t.Errorf("unexpected run %+v", run)
 		}
-		var ah string
-		if err = json.Unmarshal(tmp[swaggerHostKey], &ah); err != nil {
+		if len(storageHdl.Items) != 2 {
+			t.Errorf("expected 2 items, got %d", len(storageHdl.Items))
+		}
+		if _, ok := srv.failed["ph2"]; !ok || len(srv.failed) != 1 {
+			t.Errorf("expected failed service, got %v", srv.failed)
+		}
+		docClt.Errs = nil
+		if _, err = srv.SwaggerRefreshService(context.Background(), "ph2"); err != nil {
 			t.Fatal(err)
 		}
-		if ah != "test.test" {
-			t.Errorf("expected test.test, got %s", ah)
+		if len(srv.failed) != 0 || len(storageHdl.Items) != 3 {
+			t.Errorf("unexpected state, failed %v, items %d", srv.failed, len(storageHdl.Items))
+		}
+	})
+	t.Run("not found", func(t *testing.T) {
+		var nfe *lib_models.NotFoundError
+		if _, err := srv.SwaggerRefreshService(context.Background(), "test"); !errors.As(err, &nfe) {
+			t.Errorf("expected NotFoundError, got %v", err)
+		}
+	})
+	t.Run("discovery error", func(t *testing.T) {
+		discoveryHdl.Err = errors.New("test")
+		defer func() { discoveryHdl.Err = nil }()
+		if _, err := srv.SwaggerRefreshService(context.Background(), "ph0"); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func Test_findServices(t *testing.T) {
+	services := map[string]models.Service{
+		"a": {ID: "a", Host: "host-a", ExtPaths: []string{"/x", "/y/z"}},
+		"b": {ID: "b", Host: "host-b", ExtPaths: []string{"/x"}},
+		"c": {ID: "c", Host: "host-b"},
+	}
+	tests := map[string][]string{
+		"a":      {"a"},
+		"HOST-B": {"b", "c"},
+		"a_y_z":  {"a"},
+		"b_x":    {"b"},
+		"test":   nil,
+	}
+	for ref, a := range tests {
+		t.Run(ref, func(t *testing.T) {
+			var b []string
+			for id := range findServices(services, ref) {
+				b = append(b, id)
+			}
+			slices.Sort(b)
+			if !reflect.DeepEqual(a, b) {
+				t.Errorf("expected %v, got %v", a, b)
+			}
+		})
+	}
+}
+
+func Test_getServiceState(t *testing.T) {
+	service := models.Service{ID: "ph0", ExtPaths: []string{"/a", "/b"}}
+	storedItems := map[string]models.StorageData{
+		"ph0_a": {ID: "ph0_a", Args: [][2]string{{docPathArgKey, "/doc"}, {configHashArgKey, "x"}, {etagArgKey, "e"}}},
+		"ph0_b": {ID: "ph0_b", Args: [][2]string{{docPathArgKey, "/doc"}, {configHashArgKey, "y"}, {lastModifiedArgKey, "l"}}},
+	}
+	tests := []struct {
+		configHash string
+		expected   serviceState
+	}{
+		{"x", serviceState{docPath: "/doc", validators: doc_clt.Validators{ETag: "e"}}},
+		{"y", serviceState{docPath: "/doc", validators: doc_clt.Validators{LastModified: "l"}}},
+		{"z", serviceState{docPath: "/doc"}},
+		{"", serviceState{docPath: "/doc"}},
+	}
+	for _, tc := range tests {
+		if b := getServiceState(service, tc.configHash, storedItems); b != tc.expected {
+			t.Errorf("'%s': expected %v, got %v", tc.configHash, tc.expected, b)
 		}
 	}
+	if b := getServiceState(service, "x", nil); b != (serviceState{}) {
+		t.Errorf("expected empty state, got %v", b)
+	}
+}
+
+func Test_getDocPaths(t *testing.T) {
+	docPaths := []string{"/doc", "/v3/api-docs", "/doc", "/openapi.json"}
+	t.Run("no last path", func(t *testing.T) {
+		a := []string{"/doc", "/v3/api-docs", "/openapi.json"}
+		if b := getDocPaths(docPaths, ""); !reflect.DeepEqual(a, b) {
+			t.Errorf("expected %v, got %v", a, b)
+		}
+	})
+	t.Run("last path", func(t *testing.T) {
+		a := []string{"/openapi.json", "/doc", "/v3/api-docs"}
+		if b := getDocPaths(docPaths, "/openapi.json"); !reflect.DeepEqual(a, b) {
+			t.Errorf("expected %v, got %v", a, b)
+		}
+	})
+	t.Run("unknown last path", func(t *testing.T) {
+		a := []string{"/doc", "/v3/api-docs", "/openapi.json"}
+		if b := getDocPaths(docPaths, "/swagger/doc.json"); !reflect.DeepEqual(a, b) {
+			t.Errorf("expected %v, got %v", a, b)
+		}
+	})
 }
 
 func Test_validateSwaggerKeys(t *testing.T) {
@@ -246,16 +1160,19 @@ func TestHandler_cleanOldServices(t *testing.T) {
 			},
 		},
 	}
-	srv := New(sHdl, nil, nil, nil, 0, "", "")
-	err := srv.cleanOldServices(context.Background(), map[string]models.Service{
+	srv := New(context.Background(), sHdl, nil, nil, nil, nil, 0, "", "", ProcurementOptions{})
+	skipped, err := srv.cleanOldServices(context.Background(), map[string]models.Service{
 		"id-2": {
 			ID:       "id-2",
 			ExtPaths: []string{"/t"},
 		},
-	})
+	}, false)
 	if err != nil {
 		t.Error(err)
 	}
+	if len(skipped) != 0 {
+		t.Errorf("expected 0 skipped, got %v", skipped)
+	}
 	if len(sHdl.Items) != 1 {
 		t.Errorf("expected 1 item, got %d", len(sHdl.Items))
 	}
@@ -264,20 +1181,146 @@ func TestHandler_cleanOldServices(t *testing.T) {
 	}
 }
 
+func TestHandler_cleanOldServicesDeletionLimit(t *testing.T) {
+	newStorageHdl := func() *storageHdlMock {
+		sHdl := &storageHdlMock{}
+		for _, id := range []string{"id-1_t", "id-2_t", "id-3_t", "id-4_t"} {
+			if err := sHdl.Write(context.Background(), id, nil, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return sHdl
+	}
+	services := map[string]models.Service{
+		"id-1": {ID: "id-1", ExtPaths: []string{"/t"}},
+	}
+	util.InitLogger(struct_logger.Config{}, os.Stderr, "", "")
+	InitLogger()
+	t.Run("exceeded", func(t *testing.T) {
+		sHdl := newStorageHdl()
+		srv := New(context.Background(), sHdl, nil, nil, nil, nil, 0, "", "", ProcurementOptions{MaxDeletionPercent: 50})
+		skipped, err := srv.cleanOldServices(context.Background(), services, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a := []string{"id-2_t", "id-3_t", "id-4_t"}
+		if !reflect.DeepEqual(a, skipped) {
+			t.Errorf("expected %v, got %v", a, skipped)
+		}
+		if len(sHdl.Items) != 4 {
+			t.Errorf("expected 4 items, got %d", len(sHdl.Items))
+		}
+	})
+	t.Run("force", func(t *testing.T) {
+		sHdl := newStorageHdl()
+		srv := New(context.Background(), sHdl, nil, nil, nil, nil, 0, "", "", ProcurementOptions{MaxDeletionPercent: 50})
+		skipped, err := srv.cleanOldServices(context.Background(), services, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(skipped) != 0 || len(sHdl.Items) != 1 {
+			t.Errorf("expected 1 item and 0 skipped, got %d and %v", len(sHdl.Items), skipped)
+		}
+	})
+	t.Run("within limit", func(t *testing.T) {
+		sHdl := newStorageHdl()
+		srv := New(context.Background(), sHdl, nil, nil, nil, nil, 0, "", "", ProcurementOptions{MaxDeletionPercent: 75})
+		skipped, err := srv.cleanOldServices(context.Background(), services, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(skipped) != 0 || len(sHdl.Items) != 1 {
+			t.Errorf("expected 1 item and 0 skipped, got %d and %v", len(sHdl.Items), skipped)
+		}
+	})
+	t.Run("report", func(t *testing.T) {
+		sHdl := newStorageHdl()
+		reportHdl := &reportHdlMock{}
+		srv := New(context.Background(), sHdl, &discoveryHdlMock{}, nil, nil, reportHdl, 0, "", "", ProcurementOptions{MaxDeletionPercent: 50})
+		if err := srv.SwaggerRefreshDocs(context.Background(), false); err != nil {
+			t.Fatal(err)
+		}
+		if len(reportHdl.Runs) != 1 || len(reportHdl.Runs[0].SkippedDeletions) != 4 {
+			t.Errorf("expected 4 skipped deletions in report, got %v", reportHdl.Runs)
+		}
+		if err := srv.SwaggerRefreshDocs(context.Background(), true); err != nil {
+			t.Fatal(err)
+		}
+		if len(reportHdl.Runs[1].SkippedDeletions) != 0 || len(sHdl.Items) != 0 {
+			t.Errorf("expected 0 items, got %d", len(sHdl.Items))
+		}
+	})
+}
+
+func Test_exceedsDeletionLimit(t *testing.T) {
+	tests := []struct {
+		deletions, total, maxPercent int
+		exceeded                     bool
+	}{
+		{0, 0, 50, false},
+		{1, 2, 50, false},
+		{2, 3, 50, true},
+		{10, 10, 0, false},
+		{10, 10, 100, false},
+		{1, 100, 0, false},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%d/%d/%d", tc.deletions, tc.total, tc.maxPercent), func(t *testing.T) {
+			if b := exceedsDeletionLimit(tc.deletions, tc.total, tc.maxPercent); b != tc.exceeded {
+				t.Errorf("expected %v, got %v", tc.exceeded, b)
+			}
+		})
+	}
+}
+
+func removeArgs(args [][2]string, keys ...string) [][2]string {
+	var newArgs [][2]string
+	for _, arg := range args {
+		if !slices.Contains(keys, arg[0]) {
+			newArgs = append(newArgs, arg)
+		}
+	}
+	return newArgs
+}
+
 type docCltMock struct {
-	Docs map[string][]byte
-	Err  error
+	Docs       map[string][]byte
+	Validators map[string]doc_clt.Validators
+	Errs       map[string]error
+	Err        error
+	Calls      []string
+	Delay      time.Duration
+	MaxActive  int
+	active     int
+	mu         sync.Mutex
 }
 
-func (m *docCltMock) GetDoc(_ context.Context, protocol, host string, port int) ([]byte, error) {
+func (m *docCltMock) GetDoc(_ context.Context, protocol, host string, port int, docPath string, validators doc_clt.Validators) (doc_clt.Doc, error) {
 	if m.Err != nil {
-		return nil, m.Err
+		return doc_clt.Doc{}, m.Err
 	}
-	b, ok := m.Docs[fmt.Sprintf("%s%s%d", protocol, host, port)]
+	key := fmt.Sprintf("%s%s%d%s", protocol, host, port, docPath)
+	m.mu.Lock()
+	m.Calls = append(m.Calls, key)
+	m.active++
+	m.MaxActive = max(m.MaxActive, m.active)
+	m.mu.Unlock()
+	time.Sleep(m.Delay)
+	m.mu.Lock()
+	m.active--
+	m.mu.Unlock()
+	if err, ok := m.Errs[key]; ok {
+		return doc_clt.Doc{}, err
+	}
+	b, ok := m.Docs[key]
 	if !ok {
-		return nil, errors.New("not found")
+		return doc_clt.Doc{}, errors.New("not found")
 	}
-	return b, nil
+	v := m.Validators[key]
+	if (v.ETag != "" && v.ETag == validators.ETag) || (v.LastModified != "" && v.LastModified == validators.LastModified) {
+		return doc_clt.Doc{}, doc_clt.ErrNotModified
+	}
+	return doc_clt.Doc{Data: b, Format: models.DocFormatJSON, Validators: v}, nil
 }
 
 type discoveryHdlMock struct {
@@ -297,13 +1340,21 @@ type storageHdlMock struct {
 		models.StorageData
 		data []byte
 	}
+	Revisions map[string][]storageRevisionMock
 	Err       error
 	WriteErr  error
 	ReadErr   error
 	DeleteErr error
+	Writes    int
 	mu        sync.RWMutex
 }
 
+type storageRevisionMock struct {
+	ID   string
+	Args [][2]string
+	data []byte
+}
+
 func (m *storageHdlMock) List(_ context.Context) ([]models.StorageData, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -326,6 +1377,13 @@ func (m *storageHdlMock) Write(_ context.Context, id string, args [][2]string, d
 	if m.WriteErr != nil {
 		return m.WriteErr
 	}
+	m.Writes++
+	if m.Items == nil {
+		m.Items = make(map[string]struct {
+			models.StorageData
+			data []byte
+		})
+	}
 	m.Items[id] = struct {
 		models.StorageData
 		data []byte
@@ -339,6 +1397,61 @@ func (m *storageHdlMock) Write(_ context.Context, id string, args [][2]string, d
 	return nil
 }
 
+func (m *storageHdlMock) SetArgs(_ context.Context, id string, args [][2]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+	item, ok := m.Items[id]
+	if !ok {
+		return errors.New("not found")
+	}
+	item.Args = args
+	m.Items[id] = item
+	return nil
+}
+
+func (m *storageHdlMock) ListRevisions(_ context.Context, id string) ([]lib_models.DocRevision, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	item, ok := m.Items[id]
+	if !ok {
+		return nil, lib_models.NewNotFoundError(errors.New("not found"))
+	}
+	revisions := []lib_models.DocRevision{{ID: id + "-0", Hash: getHash(item.data), Args: item.Args}}
+	for _, revision := range m.Revisions[id] {
+		revisions = append(revisions, lib_models.DocRevision{ID: revision.ID, Hash: getHash(revision.data), Args: revision.Args})
+	}
+	return revisions, nil
+}
+
+func (m *storageHdlMock) getRevision(id, revisionID string) (storageRevisionMock, bool) {
+	item, ok := m.Items[id]
+	if !ok {
+		return storageRevisionMock{}, false
+	}
+	if revisionID == id+"-0" {
+		return storageRevisionMock{ID: revisionID, Args: item.Args, data: item.data}, true
+	}
+	for _, revision := range m.Revisions[id] {
+		if revision.ID == revisionID {
+			return revision, true
+		}
+	}
+	return storageRevisionMock{}, false
+}
+
+func (m *storageHdlMock) ReadRevision(_ context.Context, id, revisionID string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	revision, ok := m.getRevision(id, revisionID)
+	if !ok {
+		return nil, lib_models.NewNotFoundError(errors.New("not found"))
+	}
+	return revision.data, nil
+}
+
 func (m *storageHdlMock) Read(_ context.Context, id string) ([]byte, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -371,3 +1484,75 @@ func (m *storageHdlMock) Delete(_ context.Context, id string) error {
 	delete(m.Items, id)
 	return nil
 }
+
+func (m *storageHdlMock) Pin(_ context.Context, id, revisionID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.getRevision(id, revisionID); !ok {
+		return lib_models.NewNotFoundError(errors.New("not found"))
+	}
+	item := m.Items[id]
+	item.PinnedRevision = revisionID
+	m.Items[id] = item
+	return nil
+}
+
+func (m *storageHdlMock) Unpin(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	item, ok := m.Items[id]
+	if !ok {
+		return lib_models.NewNotFoundError(errors.New("not found"))
+	}
+	item.PinnedRevision = ""
+	m.Items[id] = item
+	return nil
+}
+
+func (m *storageHdlMock) Rollback(_ context.Context, id, revisionID string, args [][2]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	revision, ok := m.getRevision(id, revisionID)
+	if !ok {
+		return lib_models.NewNotFoundError(errors.New("not found"))
+	}
+	item := m.Items[id]
+	item.Args = args
+	item.data = revision.data
+	item.PinnedRevision = ""
+	m.Items[id] = item
+	return nil
+}
+
+type reportHdlMock struct {
+	Runs []lib_models.ProcurementRun
+	mu   sync.Mutex
+}
+
+func (m *reportHdlMock) Put(_ context.Context, run lib_models.ProcurementRun) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Runs = append(m.Runs, run)
+	return nil
+}
+
+func (m *reportHdlMock) Get(_ context.Context, id string) (lib_models.ProcurementRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, run := range m.Runs {
+		if run.ID == id {
+			return run, nil
+		}
+	}
+	return lib_models.ProcurementRun{}, lib_models.NewNotFoundError(errors.New("not found"))
+}
+
+func (m *reportHdlMock) List(_ context.Context) ([]lib_models.ProcurementRunInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var infos []lib_models.ProcurementRunInfo
+	for i := len(m.Runs) - 1; i >= 0; i-- {
+		infos = append(infos, m.Runs[i].ProcurementRunInfo)
+	}
+	return infos, nil
+}
@@ -0,0 +1,362 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package swagger_srv
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	refKey           = "$ref"
+	discriminatorKey = "discriminator"
+	mappingKey       = "mapping"
+)
+
+var swaggerRefContainers = [][]string{
+	{swaggerDefinitionsKey},
+	{swaggerParametersKey},
+	{swaggerResponsesKey},
+}
+
+func getRefs(raw []byte, refs map[string]struct{}) error {
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return err
+	}
+	walkRefs(v, refs)
+	return nil
+}
+
+func walkRefs(v any, refs map[string]struct{}) {
+	switch t := v.(type) {
+	case map[string]any:
+		for key, val := range t {
+			switch key {
+			case refKey:
+				if str, ok := val.(string); ok {
+					addRef(refs, str)
+					continue
+				}
+			case discriminatorKey:
+				if obj, ok := val.(map[string]any); ok {
+					if mapping, ok := obj[mappingKey].(map[string]any); ok {
+						for _, m := range mapping {
+							if str, ok := m.(string); ok {
+								addRef(refs, str)
+							}
+						}
+					}
+				}
+			}
+			walkRefs(val, refs)
+		}
+	case []any:
+		for _, item := range t {
+			walkRefs(item, refs)
+		}
+	}
+}
+
+func addRef(refs map[string]struct{}, ref string) {
+	tokens, ok := parseRef(ref)
+	if !ok {
+		return
+	}
+	refs[joinPointer(tokens)] = struct{}{}
+}
+
+func parseRef(ref string) ([]string, bool) {
+	fragment, ok := strings.CutPrefix(strings.TrimSpace(ref), "#")
+	if !ok {
+		return nil, false
+	}
+	fragment, err := url.PathUnescape(fragment)
+	if err != nil {
+		return nil, false
+	}
+	return splitPointer(fragment)
+}
+
+func splitPointer(pointer string) ([]string, bool) {
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, true
+}
+
+func joinPointer(tokens []string) string {
+	var sb strings.Builder
+	for _, token := range tokens {
+		sb.WriteString("/")
+		sb.WriteString(strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1"))
+	}
+	return sb.String()
+}
+
+func getRefContainers(doc map[string]json.RawMessage) [][]string {
+	if isOpenApiV3(doc) {
+		var containers [][]string
+		for _, section := range openApiRefComponents {
+			containers = append(containers, []string{swaggerComponentsKey, section})
+		}
+		return containers
+	}
+	return swaggerRefContainers
+}
+
+func pruneDocRefs(doc map[string]json.RawMessage, refs map[string]struct{}) error {
+	containers := getRefContainers(doc)
+	containerItems := make(map[string]map[string]json.RawMessage)
+	for _, cPath := range containers {
+		items, err := getContainer(doc, cPath)
+		if err != nil {
+			return err
+		}
+		if items != nil {
+			containerItems[joinPointer(cPath)] = items
+		}
+	}
+	if len(containerItems) == 0 {
+		return nil
+	}
+	queue := make([]string, 0, len(refs))
+	for ref := range refs {
+		queue = append(queue, ref)
+	}
+	rootRefs := make(map[string]struct{})
+	if err := getRootRefs(doc, containers, rootRefs); err != nil {
+		return err
+	}
+	for ref := range rootRefs {
+		if _, ok := refs[ref]; !ok {
+			queue = append(queue, ref)
+		}
+	}
+	visited := make(map[string]struct{})
+	used := make(map[string]map[string]struct{})
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[ref]; ok {
+			continue
+		}
+		visited[ref] = struct{}{}
+		tokens, ok := splitPointer(ref)
+		if !ok {
+			continue
+		}
+		var raw json.RawMessage
+		if cKey, name, ok := matchContainer(containers, tokens); ok {
+			if _, ok := used[cKey][name]; ok {
+				continue
+			}
+			if used[cKey] == nil {
+				used[cKey] = make(map[string]struct{})
+			}
+			used[cKey][name] = struct{}{}
+			raw, ok = containerItems[cKey][name]
+			if !ok {
+				continue
+			}
+		} else {
+			raw, ok = resolvePointer(doc, tokens)
+			if !ok {
+				continue
+			}
+		}
+		newRefs := make(map[string]struct{})
+		if err := getRefs(raw, newRefs); err != nil {
+			return err
+		}
+		for newRef := range newRefs {
+			if _, ok := visited[newRef]; !ok {
+				queue = append(queue, newRef)
+			}
+		}
+	}
+	for _, cPath := range containers {
+		cKey := joinPointer(cPath)
+		items, ok := containerItems[cKey]
+		if !ok {
+			continue
+		}
+		newItems := make(map[string]json.RawMessage)
+		for name := range used[cKey] {
+			if rawMessage, ok := items[name]; ok {
+				newItems[name] = rawMessage
+			}
+		}
+		if err := setContainer(doc, cPath, newItems); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func getRootRefs(doc map[string]json.RawMessage, containers [][]string, refs map[string]struct{}) error {
+	excluded := make(map[string]map[string]struct{})
+	for _, cPath := range containers {
+		sub, ok := excluded[cPath[0]]
+		if !ok {
+			sub = make(map[string]struct{})
+			excluded[cPath[0]] = sub
+		}
+		if len(cPath) > 1 {
+			sub[cPath[1]] = struct{}{}
+		}
+	}
+	for key, raw := range doc {
+		if key == swaggerPathsKey {
+			continue
+		}
+		sub, ok := excluded[key]
+		if !ok {
+			if err := getRefs(raw, refs); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(sub) == 0 {
+			continue
+		}
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return err
+		}
+		for subKey, subRaw := range obj {
+			if _, ok := sub[subKey]; ok {
+				continue
+			}
+			if err := getRefs(subRaw, refs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func matchContainer(containers [][]string, tokens []string) (string, string, bool) {
+	for _, cPath := range containers {
+		if len(tokens) <= len(cPath) {
+			continue
+		}
+		ok := true
+		for i, key := range cPath {
+			if tokens[i] != key {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return joinPointer(cPath), tokens[len(cPath)], true
+		}
+	}
+	return "", "", false
+}
+
+func resolvePointer(doc map[string]json.RawMessage, tokens []string) (json.RawMessage, bool) {
+	if len(tokens) == 0 {
+		return nil, false
+	}
+	raw, ok := doc[tokens[0]]
+	if !ok {
+		return nil, false
+	}
+	for _, token := range tokens[1:] {
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &obj); err == nil {
+			if raw, ok = obj[token]; !ok {
+				return nil, false
+			}
+			continue
+		}
+		var arr []json.RawMessage
+		if err := json.Unmarshal(raw, &arr); err != nil {
+			return nil, false
+		}
+		i, err := strconv.Atoi(token)
+		if err != nil || i < 0 || i >= len(arr) {
+			return nil, false
+		}
+		raw = arr[i]
+	}
+	return raw, true
+}
+
+func getContainer(doc map[string]json.RawMessage, cPath []string) (map[string]json.RawMessage, error) {
+	raw, ok := doc[cPath[0]]
+	if !ok {
+		return nil, nil
+	}
+	for _, key := range cPath[1:] {
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return nil, err
+		}
+		if raw, ok = obj[key]; !ok {
+			return nil, nil
+		}
+	}
+	var items map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func setContainer(obj map[string]json.RawMessage, cPath []string, items map[string]json.RawMessage) error {
+	if len(cPath) == 1 {
+		if len(items) == 0 {
+			delete(obj, cPath[0])
+			return nil
+		}
+		b, err := json.Marshal(items)
+		if err != nil {
+			return err
+		}
+		obj[cPath[0]] = b
+		return nil
+	}
+	raw, ok := obj[cPath[0]]
+	if !ok {
+		return nil
+	}
+	var child map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &child); err != nil {
+		return err
+	}
+	if err := setContainer(child, cPath[1:], items); err != nil {
+		return err
+	}
+	if len(child) == 0 {
+		delete(obj, cPath[0])
+		return nil
+	}
+	b, err := json.Marshal(child)
+	if err != nil {
+		return err
+	}
+	obj[cPath[0]] = b
+	return nil
+}
@@ -0,0 +1,153 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package swagger_srv
+
+import (
+	"context"
+	lib_models "github.com/SENERGY-Platform/api-docs-provider/lib/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util/slog_attr"
+	"github.com/SENERGY-Platform/go-service-base/struct-logger/attributes"
+	"github.com/google/uuid"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+type runReport struct {
+	run lib_models.ProcurementRun
+	mu  sync.Mutex
+}
+
+func newRunReport(runType string) *runReport {
+	return &runReport{
+		run: lib_models.ProcurementRun{
+			ProcurementRunInfo: lib_models.ProcurementRunInfo{
+				ID:    uuid.NewString(),
+				Type:  runType,
+				Start: time.Now().UTC(),
+			},
+		},
+	}
+}
+
+func (r *runReport) setDiscovered(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.run.Discovered = n
+}
+
+func (r *runReport) addService(result lib_models.ProcurementServiceResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.run.Services = append(r.run.Services, result)
+}
+
+func (r *runReport) setSkippedDeletions(ids []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.run.SkippedDeletions = ids
+}
+
+func (r *runReport) progress() lib_models.ProcurementJobProgress {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return lib_models.ProcurementJobProgress{
+		Total: r.run.Discovered,
+		Done:  len(r.run.Services),
+	}
+}
+
+func (r *runReport) finish(err error) lib_models.ProcurementRun {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.run.End = time.Now().UTC()
+	if err != nil {
+		r.run.Error = err.Error()
+	}
+	r.run.Outcomes = make(map[string]int)
+	for _, result := range r.run.Services {
+		r.run.Outcomes[result.Outcome]++
+	}
+	slices.SortFunc(r.run.Services, func(a, b lib_models.ProcurementServiceResult) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return r.run
+}
+
+func (s *Service) SwaggerGetProcurementStatus(ctx context.Context) (lib_models.ProcurementStatus, error) {
+	runs, err := s.reportHdl.List(ctx)
+	if err != nil {
+		return lib_models.ProcurementStatus{}, err
+	}
+	return lib_models.ProcurementStatus{
+		Running: s.running.Load(),
+		Runs:    runs,
+	}, nil
+}
+
+func (s *Service) SwaggerGetProcurementRun(ctx context.Context, id string) (lib_models.ProcurementRun, error) {
+	return s.reportHdl.Get(ctx, id)
+}
+
+func (s *Service) saveReport(ctx context.Context, report *runReport, err error) lib_models.ProcurementRun {
+	run := report.finish(err)
+	logger.Info("procurement run finished", slog_attr.IDKey, run.ID, slog_attr.NumberKey, run.Discovered, slog_attr.OutcomesKey, run.Outcomes, slog_attr.RequestIDKey, util.GetReqID(ctx))
+	if err := s.reportHdl.Put(context.WithoutCancel(ctx), run); err != nil {
+		logger.Error("saving procurement report failed", slog_attr.IDKey, run.ID, attributes.ErrorKey, err, slog_attr.RequestIDKey, util.GetReqID(ctx))
+	}
+	return run
+}
+
+func newInvalidResult(result lib_models.ProcurementServiceResult, err error) lib_models.ProcurementServiceResult {
+	result.Outcome = lib_models.ProcurementOutcomeInvalid
+	result.Error = err.Error()
+	return result
+}
+
+func getServiceOutcome(docs []lib_models.ProcurementDocResult) string {
+	outcomes := make(map[string]struct{})
+	for _, doc := range docs {
+		outcomes[doc.Outcome] = struct{}{}
+	}
+	for _, outcome := range []string{
+		lib_models.ProcurementOutcomeFetched,
+		lib_models.ProcurementOutcomeUnchanged,
+		lib_models.ProcurementOutcomeFailed,
+		lib_models.ProcurementOutcomeInvalid,
+	} {
+		if _, ok := outcomes[outcome]; ok {
+			return outcome
+		}
+	}
+	return lib_models.ProcurementOutcomeFilteredOut
+}
+
+func getDeniedDocResults(service models.Service) []lib_models.ProcurementDocResult {
+	var results []lib_models.ProcurementDocResult
+	for _, extPath := range service.DeniedPaths {
+		results = append(results, lib_models.ProcurementDocResult{
+			ID:       getStorageID(service.ID, extPath),
+			BasePath: extPath,
+			Outcome:  lib_models.ProcurementOutcomeFilteredOut,
+			Reason:   lib_models.ProcurementReasonDenied,
+		})
+	}
+	return results
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package swagger_srv
+
+import (
+	"context"
+	"errors"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/components/doc_clt"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util/slog_attr"
+	"github.com/SENERGY-Platform/go-service-base/struct-logger/attributes"
+	"io"
+	"math/rand/v2"
+	"net"
+	"slices"
+	"time"
+)
+
+func (s *Service) fetchDoc(ctx context.Context, protocol, host string, port int, docPath string, validators doc_clt.Validators, timeout time.Duration) (doc_clt.Doc, error) {
+	for attempt := 0; ; attempt++ {
+		if err := s.hostLimiter.Wait(ctx, host); err != nil {
+			return doc_clt.Doc{}, err
+		}
+		ctxWt, cf := context.WithTimeout(ctx, timeout)
+		doc, err := s.docClt.GetDoc(ctxWt, protocol, host, port, docPath, validators)
+		cf()
+		if err == nil {
+			return doc, nil
+		}
+		if attempt >= s.procOpts.Retries || ctx.Err() != nil || !isRetryable(err) {
+			return doc_clt.Doc{}, err
+		}
+		delay := getBackoff(s.procOpts.RetryDelay, s.procOpts.RetryMaxDelay, attempt)
+		logger.Debug("fetching doc failed, retrying", slog_attr.HostKey, host, slog_attr.PortKey, port, slog_attr.DocPathKey, docPath, slog_attr.AttemptKey, attempt+1, slog_attr.DelayKey, delay.String(), attributes.ErrorKey, err, slog_attr.RequestIDKey, util.GetReqID(ctx))
+		if err = sleep(ctx, delay); err != nil {
+			return doc_clt.Doc{}, err
+		}
+	}
+}
+
+func isRetryable(err error) bool {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return slices.ContainsFunc(joined.Unwrap(), isRetryable)
+	}
+	var respErr *doc_clt.ResponseError
+	if errors.As(err, &respErr) {
+		return respErr.Code >= 500
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+func getBackoff(base, maxDelay time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+	delay := base
+	for i := 0; i < attempt && (maxDelay <= 0 || delay < maxDelay); i++ {
+		delay *= 2
+	}
+	if maxDelay > 0 && delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,246 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package swagger_srv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/components/doc_clt"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util"
+	"github.com/SENERGY-Platform/go-service-base/struct-logger"
+	"net"
+	"net/url"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_isRetryable(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected bool
+	}{
+		{doc_clt.NewResponseError(503, errors.New("test")), true},
+		{doc_clt.NewResponseError(500, errors.New("test")), true},
+		{doc_clt.NewResponseError(404, errors.New("test")), false},
+		{fmt.Errorf("/doc: %w", doc_clt.NewResponseError(502, errors.New("test"))), true},
+		{errors.Join(doc_clt.NewResponseError(404, errors.New("test")), doc_clt.NewResponseError(503, errors.New("test"))), true},
+		{&url.Error{Op: "Get", URL: "http://test", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}, true},
+		{context.DeadlineExceeded, true},
+		{errors.New("missing required keys"), false},
+	}
+	for i, tc := range tests {
+		if b := isRetryable(tc.err); b != tc.expected {
+			t.Errorf("%d: expected %v, got %v", i, tc.expected, b)
+		}
+	}
+}
+
+func Test_getBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}{
+		{0, time.Millisecond * 50, time.Millisecond * 100},
+		{1, time.Millisecond * 100, time.Millisecond * 200},
+		{2, time.Millisecond * 200, time.Millisecond * 400},
+		{5, time.Millisecond * 250, time.Millisecond * 500},
+		{100, time.Millisecond * 250, time.Millisecond * 500},
+	}
+	for _, tc := range tests {
+		for i := 0; i < 20; i++ {
+			d := getBackoff(time.Millisecond*100, time.Millisecond*500, tc.attempt)
+			if d < tc.min || d > tc.max {
+				t.Errorf("attempt %d: expected delay between %s and %s, got %s", tc.attempt, tc.min, tc.max, d)
+			}
+		}
+	}
+	if d := getBackoff(0, time.Second, 3); d != 0 {
+		t.Errorf("expected 0, got %s", d)
+	}
+}
+
+func TestService_fetchDoc(t *testing.T) {
+	util.InitLogger(struct_logger.Config{}, os.Stderr, "", "")
+	InitLogger()
+	opts := ProcurementOptions{Retries: 2, RetryDelay: time.Millisecond, RetryMaxDelay: time.Millisecond * 5}
+	t.Run("recovers", func(t *testing.T) {
+		docClt := &flakyDocCltMock{Errs: []error{doc_clt.NewResponseError(503, errors.New("test")), doc_clt.NewResponseError(502, errors.New("test"))}}
+		srv := New(context.Background(), nil, nil, docClt, nil, nil, time.Second, "", "", opts)
+		if _, err := srv.fetchDoc(context.Background(), "http", "h", 80, "/doc", doc_clt.Validators{}, time.Second); err != nil {
+			t.Fatal(err)
+		}
+		if docClt.Calls != 3 {
+			t.Errorf("expected 3 calls, got %d", docClt.Calls)
+		}
+	})
+	t.Run("exhausted", func(t *testing.T) {
+		docClt := &flakyDocCltMock{Errs: []error{context.DeadlineExceeded, context.DeadlineExceeded, context.DeadlineExceeded}}
+		srv := New(context.Background(), nil, nil, docClt, nil, nil, time.Second, "", "", opts)
+		if _, err := srv.fetchDoc(context.Background(), "http", "h", 80, "/doc", doc_clt.Validators{}, time.Second); err == nil {
+			t.Error("expected error")
+		}
+		if docClt.Calls != 3 {
+			t.Errorf("expected 3 calls, got %d", docClt.Calls)
+		}
+	})
+	t.Run("not retryable", func(t *testing.T) {
+		docClt := &flakyDocCltMock{Errs: []error{doc_clt.NewResponseError(404, errors.New("test"))}}
+		srv := New(context.Background(), nil, nil, docClt, nil, nil, time.Second, "", "", opts)
+		if _, err := srv.fetchDoc(context.Background(), "http", "h", 80, "/doc", doc_clt.Validators{}, time.Second); err == nil {
+			t.Error("expected error")
+		}
+		if docClt.Calls != 1 {
+			t.Errorf("expected 1 call, got %d", docClt.Calls)
+		}
+	})
+	t.Run("canceled", func(t *testing.T) {
+		docClt := &flakyDocCltMock{Errs: []error{doc_clt.NewResponseError(503, errors.New("test")), doc_clt.NewResponseError(503, errors.New("test"))}}
+		srv := New(context.Background(), nil, nil, docClt, nil, nil, time.Second, "", "", ProcurementOptions{Retries: 2, RetryDelay: time.Second})
+		ctx, cf := context.WithTimeout(context.Background(), time.Millisecond*10)
+		defer cf()
+		if _, err := srv.fetchDoc(ctx, "http", "h", 80, "/doc", doc_clt.Validators{}, time.Second); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded, got %v", err)
+		}
+		if docClt.Calls != 1 {
+			t.Errorf("expected 1 call, got %d", docClt.Calls)
+		}
+	})
+}
+
+func TestService_probeService(t *testing.T) {
+	validDoc, err := os.ReadFile("test/swagger.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	util.InitLogger(struct_logger.Config{}, os.Stderr, "", "")
+	InitLogger()
+	opts := ProcurementOptions{DocPaths: []string{"/a", "/b", "/c"}, Retries: 2, RetryDelay: time.Millisecond, RetryMaxDelay: time.Millisecond * 5}
+	service := models.Service{ID: "ph0", Host: "h", Port: 80, Protocol: "http"}
+	dialErr := &url.Error{Op: "Get", URL: "http://h", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}
+	t.Run("unreachable host", func(t *testing.T) {
+		docClt := &flakyDocCltMock{Errs: []error{dialErr, dialErr, dialErr, dialErr, dialErr, dialErr, dialErr, dialErr, dialErr}}
+		srv := New(context.Background(), nil, nil, docClt, nil, nil, time.Second, "", "", opts)
+		if _, _, _, err := srv.probeService(context.Background(), service, serviceState{}); err == nil {
+			t.Error("expected error")
+		}
+		if docClt.Calls != 3 {
+			t.Errorf("expected 3 calls, got %d", docClt.Calls)
+		}
+	})
+	t.Run("timeout", func(t *testing.T) {
+		docClt := &flakyDocCltMock{Errs: []error{context.DeadlineExceeded, context.DeadlineExceeded, context.DeadlineExceeded, context.DeadlineExceeded}}
+		srv := New(context.Background(), nil, nil, docClt, nil, nil, time.Second, "", "", opts)
+		if _, _, _, err := srv.probeService(context.Background(), service, serviceState{}); err == nil {
+			t.Error("expected error")
+		}
+		if docClt.Calls != 3 {
+			t.Errorf("expected 3 calls, got %d", docClt.Calls)
+		}
+	})
+	t.Run("not found", func(t *testing.T) {
+		docClt := &flakyDocCltMock{Errs: []error{doc_clt.NewResponseError(404, errors.New("test")), doc_clt.NewResponseError(404, errors.New("test"))}, Doc: validDoc}
+		srv := New(context.Background(), nil, nil, docClt, nil, nil, time.Second, "", "", opts)
+		_, _, docPath, err := srv.probeService(context.Background(), service, serviceState{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if docPath != "/c" {
+			t.Errorf("expected /c, got %s", docPath)
+		}
+		if docClt.Calls != 3 {
+			t.Errorf("expected 3 calls, got %d", docClt.Calls)
+		}
+	})
+	t.Run("non doc response", func(t *testing.T) {
+		docClt := &flakyDocCltMock{Errs: []error{doc_clt.NewResponseError(404, errors.New("test"))}, Doc: []byte(`{"test":"test"}`)}
+		srv := New(context.Background(), nil, nil, docClt, nil, nil, time.Second, "", "", opts)
+		if _, _, _, err := srv.probeService(context.Background(), service, serviceState{}); err == nil {
+			t.Error("expected error")
+		}
+		if docClt.Calls != 3 {
+			t.Errorf("expected 3 calls, got %d", docClt.Calls)
+		}
+	})
+}
+
+func TestService_refreshFailedServices(t *testing.T) {
+	validDoc, err := os.ReadFile("test/swagger.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	util.InitLogger(struct_logger.Config{}, os.Stderr, "", "")
+	InitLogger()
+	docClt := &flakyDocCltMock{
+		Errs: []error{doc_clt.NewResponseError(503, errors.New("test"))},
+		Doc:  validDoc,
+	}
+	discoveryHdl := &discoveryHdlMock{
+		Services: map[string]models.Service{
+			"ph0": {
+				ID:       "ph0",
+				Host:     "h",
+				Port:     0,
+				Protocol: "p",
+				ExtPaths: []string{"/t"},
+			},
+		},
+	}
+	storageHdl := &storageHdlMock{}
+	srv := New(context.Background(), storageHdl, discoveryHdl, docClt, nil, &reportHdlMock{}, time.Second, "test.test", "", ProcurementOptions{DocPaths: []string{"/doc"}})
+	if err = srv.SwaggerRefreshDocs(context.Background(), false); err != nil {
+		t.Fatal(err)
+	}
+	if !srv.hasFailedServices() {
+		t.Fatal("expected failed services")
+	}
+	if len(storageHdl.Items) != 0 {
+		t.Errorf("expected 0 items, got %d", len(storageHdl.Items))
+	}
+	if err = srv.refreshFailedServices(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if srv.hasFailedServices() {
+		t.Error("expected no failed services")
+	}
+	if _, ok := storageHdl.Items["ph0_t"]; !ok {
+		t.Error("expected item 'ph0_t'")
+	}
+}
+
+type flakyDocCltMock struct {
+	Errs  []error
+	Doc   []byte
+	Calls int
+	mu    sync.Mutex
+}
+
+func (m *flakyDocCltMock) GetDoc(_ context.Context, _, _ string, _ int, _ string, _ doc_clt.Validators) (doc_clt.Doc, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Calls++
+	if len(m.Errs) > 0 {
+		err := m.Errs[0]
+		m.Errs = m.Errs[1:]
+		return doc_clt.Doc{}, err
+	}
+	return doc_clt.Doc{Data: m.Doc, Format: models.DocFormatJSON}, nil
+}
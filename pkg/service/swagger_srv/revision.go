@@ -0,0 +1,131 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package swagger_srv
+
+import (
+	"context"
+	"errors"
+	lib_models "github.com/SENERGY-Platform/api-docs-provider/lib/models"
+	srv_util "github.com/SENERGY-Platform/api-docs-provider/pkg/service/util"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util/slog_attr"
+	"github.com/SENERGY-Platform/go-service-base/struct-logger/attributes"
+	"slices"
+)
+
+func (s *Service) SwaggerListRevisions(ctx context.Context, id string, userToken string, userRoles []string) ([]lib_models.DocRevision, error) {
+	if _, err := s.SwaggerGetDoc(ctx, id, userToken, userRoles); err != nil {
+		return nil, err
+	}
+	return s.storageHdl.ListRevisions(ctx, id)
+}
+
+func (s *Service) SwaggerGetRevision(ctx context.Context, id, revisionID string, userToken string, userRoles []string) ([]byte, error) {
+	reqID := util.GetReqID(ctx)
+	logger.Debug("reading doc revision", slog_attr.IDKey, id, slog_attr.RevisionKey, revisionID, slog_attr.RequestIDKey, reqID)
+	rawDoc, err := s.storageHdl.ReadRevision(ctx, id, revisionID)
+	if err != nil {
+		logger.Error("reading doc revision failed", slog_attr.IDKey, id, slog_attr.RevisionKey, revisionID, attributes.ErrorKey, err.Error(), slog_attr.RequestIDKey, reqID)
+		return nil, err
+	}
+	return s.prepareDoc(ctx, id, rawDoc, userToken, userRoles)
+}
+
+func (s *Service) SwaggerDiff(ctx context.Context, id, from, to string, userToken string, userRoles []string) (lib_models.DocDiff, error) {
+	revisions, err := s.SwaggerListRevisions(ctx, id, userToken, userRoles)
+	if err != nil {
+		return lib_models.DocDiff{}, err
+	}
+	from, to, err = srv_util.GetDiffRevisions(revisions, from, to)
+	if err != nil {
+		return lib_models.DocDiff{}, err
+	}
+	fromDoc, err := s.SwaggerGetRevision(ctx, id, from, userToken, userRoles)
+	if err != nil {
+		return lib_models.DocDiff{}, err
+	}
+	toDoc, err := s.SwaggerGetRevision(ctx, id, to, userToken, userRoles)
+	if err != nil {
+		return lib_models.DocDiff{}, err
+	}
+	return srv_util.NewDocDiff(from, to, fromDoc, toDoc)
+}
+
+func (s *Service) SwaggerPinRevision(ctx context.Context, id, revisionID string, userRoles []string) error {
+	if !stringInSlice(s.adminRoleName, userRoles) {
+		return lib_models.NewForbiddenErr(errors.New("no access rights"))
+	}
+	reqID := util.GetReqID(ctx)
+	if err := s.storageHdl.Pin(ctx, id, revisionID); err != nil {
+		logger.Error("pinning doc revision failed", slog_attr.IDKey, id, slog_attr.RevisionKey, revisionID, attributes.ErrorKey, err.Error(), slog_attr.RequestIDKey, reqID)
+		return err
+	}
+	logger.Info("pinned doc revision", slog_attr.IDKey, id, slog_attr.RevisionKey, revisionID, slog_attr.RequestIDKey, reqID)
+	return nil
+}
+
+func (s *Service) SwaggerUnpinRevision(ctx context.Context, id string, userRoles []string) error {
+	if !stringInSlice(s.adminRoleName, userRoles) {
+		return lib_models.NewForbiddenErr(errors.New("no access rights"))
+	}
+	reqID := util.GetReqID(ctx)
+	if err := s.storageHdl.Unpin(ctx, id); err != nil {
+		logger.Error("unpinning doc failed", slog_attr.IDKey, id, attributes.ErrorKey, err.Error(), slog_attr.RequestIDKey, reqID)
+		return err
+	}
+	logger.Info("unpinned doc", slog_attr.IDKey, id, slog_attr.RequestIDKey, reqID)
+	return nil
+}
+
+func (s *Service) SwaggerRollback(ctx context.Context, id, revisionID string, userRoles []string) error {
+	if !stringInSlice(s.adminRoleName, userRoles) {
+		return lib_models.NewForbiddenErr(errors.New("no access rights"))
+	}
+	reqID := util.GetReqID(ctx)
+	storedItems, err := s.getStoredItems(ctx)
+	if err != nil {
+		return err
+	}
+	storedItem, ok := storedItems[id]
+	if !ok {
+		return lib_models.NewNotFoundError(errors.New("not found"))
+	}
+	revisions, err := s.storageHdl.ListRevisions(ctx, id)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(revisions, func(revision lib_models.DocRevision) bool { return revision.ID == revisionID })
+	if i < 0 {
+		return lib_models.NewNotFoundError(errors.New("not found"))
+	}
+	doc, err := s.storageHdl.ReadRevision(ctx, id, revisionID)
+	if err != nil {
+		return err
+	}
+	args := append(removeStatusArgs(revisions[i].Args), getStatusArgs(storedItem.Args)...)
+	if i := slices.IndexFunc(args, func(arg [2]string) bool { return arg[0] == hashArgKey }); i >= 0 {
+		args[i][1] = getHash(doc)
+	} else {
+		args = append(args, [2]string{hashArgKey, getHash(doc)})
+	}
+	if err = s.storageHdl.Rollback(ctx, id, revisionID, args); err != nil {
+		logger.Error("rolling back doc failed", slog_attr.IDKey, id, slog_attr.RevisionKey, revisionID, attributes.ErrorKey, err.Error(), slog_attr.RequestIDKey, reqID)
+		return err
+	}
+	logger.Info("rolled back doc", slog_attr.IDKey, id, slog_attr.RevisionKey, revisionID, slog_attr.RequestIDKey, reqID)
+	return nil
+}
@@ -0,0 +1,182 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package swagger_srv
+
+import (
+	"context"
+	"errors"
+	lib_models "github.com/SENERGY-Platform/api-docs-provider/lib/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util"
+	struct_logger "github.com/SENERGY-Platform/go-service-base/struct-logger"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestService_Revisions(t *testing.T) {
+	util.InitLogger(struct_logger.Config{}, os.Stderr, "", "")
+	InitLogger()
+	storageHdl := &storageHdlMock{}
+	if err := storageHdl.Write(context.Background(), "id-1", nil, []byte(`{"swagger":"2.0"}`)); err != nil {
+		t.Fatal(err)
+	}
+	srv := New(context.Background(), storageHdl, nil, nil, nil, nil, 0, "", "admin", ProcurementOptions{})
+	t.Run("list", func(t *testing.T) {
+		revisions, err := srv.SwaggerListRevisions(context.Background(), "id-1", "", []string{"admin"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(revisions) != 1 || revisions[0].ID != "id-1-0" {
+			t.Errorf("unexpected revisions %v", revisions)
+		}
+	})
+	t.Run("get", func(t *testing.T) {
+		doc, err := srv.SwaggerGetRevision(context.Background(), "id-1", "id-1-0", "", []string{"admin"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(doc) != `{"swagger":"2.0"}` {
+			t.Errorf("unexpected doc %s", string(doc))
+		}
+	})
+	t.Run("diff", func(t *testing.T) {
+		var iie *lib_models.InvalidInputError
+		if _, err := srv.SwaggerDiff(context.Background(), "id-1", "", "", "", []string{"admin"}); !errors.As(err, &iie) {
+			t.Errorf("expected InvalidInputError, got %v", err)
+		}
+		diff, err := srv.SwaggerDiff(context.Background(), "id-1", "id-1-0", "", "", []string{"admin"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff.From != "id-1-0" || diff.To != "id-1-0" || diff.Breaking || len(diff.Changes) != 0 {
+			t.Errorf("unexpected diff %+v", diff)
+		}
+	})
+	t.Run("pin", func(t *testing.T) {
+		if err := srv.SwaggerPinRevision(context.Background(), "id-1", "id-1-0", []string{"admin"}); err != nil {
+			t.Fatal(err)
+		}
+		items, err := srv.SwaggerListStorage(context.Background(), "", []string{"admin"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != 1 || items[0].PinnedRevision != "id-1-0" {
+			t.Errorf("unexpected items %v", items)
+		}
+		if err = srv.SwaggerUnpinRevision(context.Background(), "id-1", []string{"admin"}); err != nil {
+			t.Fatal(err)
+		}
+		if items, err = srv.SwaggerListStorage(context.Background(), "", []string{"admin"}); err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != 1 || items[0].PinnedRevision != "" {
+			t.Errorf("unexpected items %v", items)
+		}
+		var nfe *lib_models.NotFoundError
+		if err = srv.SwaggerPinRevision(context.Background(), "id-1", "test", []string{"admin"}); !errors.As(err, &nfe) {
+			t.Errorf("expected NotFoundError, got %v", err)
+		}
+	})
+	t.Run("rollback", func(t *testing.T) {
+		if err := storageHdl.SetArgs(context.Background(), "id-1", [][2]string{{titleArgKey, "a"}, {hashArgKey, "x"}, {etagArgKey, "e"}}); err != nil {
+			t.Fatal(err)
+		}
+		if err := srv.SwaggerPinRevision(context.Background(), "id-1", "id-1-0", []string{"admin"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := srv.SwaggerRollback(context.Background(), "id-1", "id-1-0", []string{"admin"}); err != nil {
+			t.Fatal(err)
+		}
+		a := [][2]string{{titleArgKey, "a"}, {hashArgKey, getHash([]byte(`{"swagger":"2.0"}`))}, {etagArgKey, "e"}}
+		if item := storageHdl.Items["id-1"]; !reflect.DeepEqual(a, item.Args) || item.PinnedRevision != "" {
+			t.Errorf("unexpected item %v", item.StorageData)
+		}
+		var nfe *lib_models.NotFoundError
+		if err := srv.SwaggerRollback(context.Background(), "id-2", "id-1-0", []string{"admin"}); !errors.As(err, &nfe) {
+			t.Errorf("expected NotFoundError, got %v", err)
+		}
+		if err := srv.SwaggerRollback(context.Background(), "id-1", "test", []string{"admin"}); !errors.As(err, &nfe) {
+			t.Errorf("expected NotFoundError, got %v", err)
+		}
+	})
+	t.Run("rollback revision args", func(t *testing.T) {
+		storageHdl := &storageHdlMock{}
+		oldDoc := []byte(`{"swagger":"2.0","info":{"title":"a"}}`)
+		if err := storageHdl.Write(context.Background(), "id-1", [][2]string{{titleArgKey, "b"}, {versionArgKey, "2"}, {hashArgKey, "y"}, {routeArgKey, "/b|GET"}, {lastFetchedArgKey, "2025-01-02T00:00:00Z"}, {lastErrorArgKey, "err"}}, []byte(`{"swagger":"2.0","info":{"title":"b"}}`)); err != nil {
+			t.Fatal(err)
+		}
+		storageHdl.Revisions = map[string][]storageRevisionMock{
+			"id-1": {{ID: "id-1-1", Args: [][2]string{{titleArgKey, "a"}, {versionArgKey, "1"}, {hashArgKey, "x"}, {routeArgKey, "/a|GET"}, {lastFetchedArgKey, "2025-01-01T00:00:00Z"}}, data: oldDoc}},
+		}
+		srv := New(context.Background(), storageHdl, nil, nil, nil, nil, 0, "", "admin", ProcurementOptions{})
+		if err := srv.SwaggerRollback(context.Background(), "id-1", "id-1-1", []string{"admin"}); err != nil {
+			t.Fatal(err)
+		}
+		a := [][2]string{{titleArgKey, "a"}, {versionArgKey, "1"}, {hashArgKey, getHash(oldDoc)}, {routeArgKey, "/a|GET"}, {lastFetchedArgKey, "2025-01-02T00:00:00Z"}, {lastErrorArgKey, "err"}}
+		if item := storageHdl.Items["id-1"]; !reflect.DeepEqual(a, item.Args) || string(item.data) != string(oldDoc) {
+			t.Errorf("expected %v, got %v", a, item.Args)
+		}
+		items, err := srv.SwaggerListStorage(context.Background(), "", []string{"admin"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != 1 || items[0].Title != "a" || items[0].Version != "1" || items[0].LastError != "err" {
+			t.Errorf("unexpected items %v", items)
+		}
+		routes, err := getRoutes(storageHdl.Items["id-1"].Args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b := map[string][]string{"/a": {"GET"}}; !reflect.DeepEqual(b, routes) {
+			t.Errorf("expected %v, got %v", b, routes)
+		}
+	})
+	t.Run("forbidden", func(t *testing.T) {
+		var fe *lib_models.ForbiddenError
+		if err := srv.SwaggerPinRevision(context.Background(), "id-1", "id-1-0", []string{"user"}); !errors.As(err, &fe) {
+			t.Errorf("expected ForbiddenError, got %v", err)
+		}
+		if err := srv.SwaggerUnpinRevision(context.Background(), "id-1", nil); !errors.As(err, &fe) {
+			t.Errorf("expected ForbiddenError, got %v", err)
+		}
+		if err := srv.SwaggerRollback(context.Background(), "id-1", "id-1-0", []string{"user"}); !errors.As(err, &fe) {
+			t.Errorf("expected ForbiddenError, got %v", err)
+		}
+	})
+	t.Run("not found", func(t *testing.T) {
+		var nfe *lib_models.NotFoundError
+		if _, err := srv.SwaggerGetRevision(context.Background(), "id-1", "test", "", []string{"admin"}); !errors.As(err, &nfe) {
+			t.Errorf("expected NotFoundError, got %v", err)
+		}
+		if _, err := srv.SwaggerListRevisions(context.Background(), "id-2", "", []string{"admin"}); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func Test_getLatestData(t *testing.T) {
+	a := models.StorageData{ID: "a", Args: [][2]string{{"k", "latest"}}, PinnedRevision: "r"}
+	b := getLatestData(models.StorageData{ID: "a", Args: [][2]string{{"k", "pinned"}}, PinnedRevision: "r", LatestArgs: [][2]string{{"k", "latest"}}})
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("expected %v, got %v", a, b)
+	}
+	c := models.StorageData{ID: "a", Args: [][2]string{{"k", "v"}}}
+	if b = getLatestData(c); !reflect.DeepEqual(c, b) {
+		t.Errorf("expected %v, got %v", c, b)
+	}
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package swagger_srv
+
+import (
+	"encoding/json"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+	"net"
+	"path"
+	"slices"
+	"strings"
+)
+
+func getServiceRoute(service models.Service, extPath string) models.Route {
+	if route, ok := service.Routes[extPath]; ok {
+		return route
+	}
+	return models.Route{StripPath: true}
+}
+
+func getRouteBasePath(route models.Route, extPath, docBasePath string) string {
+	if route.StripPath {
+		return extPath
+	}
+	return docBasePath
+}
+
+func getDocBasePath(doc map[string]json.RawMessage) string {
+	basePath, err := getBasePath(doc)
+	if err != nil || basePath == "" {
+		return "/"
+	}
+	return basePath
+}
+
+func routeMatchesHost(route models.Route, host string) bool {
+	if len(route.Hosts) == 0 || host == "" {
+		return true
+	}
+	for _, rHost := range route.Hosts {
+		if matchHost(rHost, host) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchHost(pattern, host string) bool {
+	if _, _, err := net.SplitHostPort(pattern); err != nil {
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+	}
+	pattern = strings.ToLower(pattern)
+	host = strings.ToLower(host)
+	if prefix, ok := strings.CutPrefix(pattern, "*"); ok {
+		return strings.HasSuffix(host, prefix) && len(host) > len(prefix)
+	}
+	if suffix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(host, suffix) && len(host) > len(suffix)
+	}
+	return pattern == host
+}
+
+func getRoutePaths(paths map[string]map[string]json.RawMessage, route models.Route, extPath, docBasePath string) (map[string]map[string]json.RawMessage, bool) {
+	newPaths := make(map[string]map[string]json.RawMessage)
+	changed := false
+	for pth, item := range paths {
+		if !route.StripPath && !strings.HasPrefix(path.Join(docBasePath, pth), extPath) {
+			changed = true
+			continue
+		}
+		newItem := make(map[string]json.RawMessage)
+		hasMethods, removed := false, false
+		for key, raw := range item {
+			if !slices.Contains(httpMethods, key) {
+				newItem[key] = raw
+				continue
+			}
+			if len(route.Methods) > 0 && !slices.Contains(route.Methods, strings.ToUpper(key)) {
+				removed = true
+				continue
+			}
+			newItem[key] = raw
+			hasMethods = true
+		}
+		if removed {
+			changed = true
+			if !hasMethods {
+				continue
+			}
+		}
+		newPaths[pth] = newItem
+	}
+	return newPaths, changed
+}
+
+func setRoutePaths(doc map[string]json.RawMessage, paths map[string]map[string]json.RawMessage) error {
+	if err := setDocPaths(doc, paths); err != nil {
+		return err
+	}
+	refs := make(map[string]struct{})
+	for _, item := range paths {
+		for _, raw := range item {
+			if err := getRefs(raw, refs); err != nil {
+				return err
+			}
+		}
+	}
+	return cleanupDoc(doc, refs)
+}
@@ -0,0 +1,166 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package swagger_srv
+
+import (
+	"encoding/json"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func Test_matchHost(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		host     string
+		expected bool
+	}{
+		{"api.test", "api.test", true},
+		{"API.test", "api.TEST", true},
+		{"api.test", "api.test:443", true},
+		{"api.test:8443", "api.test:443", false},
+		{"api.test:8443", "api.test:8443", true},
+		{"*.test", "api.test", true},
+		{"*.test", ".test", false},
+		{"api.*", "api.test", true},
+		{"api.*", "web.test", false},
+		{"api.test", "web.test", false},
+	}
+	for _, tc := range tests {
+		if b := matchHost(tc.pattern, tc.host); b != tc.expected {
+			t.Errorf("'%s' '%s': expected %v, got %v", tc.pattern, tc.host, tc.expected, b)
+		}
+	}
+}
+
+func Test_routeMatchesHost(t *testing.T) {
+	if !routeMatchesHost(models.Route{}, "api.test") {
+		t.Error("expected match for route without hosts")
+	}
+	if !routeMatchesHost(models.Route{Hosts: []string{"web.test"}}, "") {
+		t.Error("expected match for empty host")
+	}
+	if !routeMatchesHost(models.Route{Hosts: []string{"web.test", "*.test"}}, "api.test") {
+		t.Error("expected match")
+	}
+	if routeMatchesHost(models.Route{Hosts: []string{"web.test"}}, "api.test") {
+		t.Error("unexpected match")
+	}
+}
+
+func Test_getRoutePaths(t *testing.T) {
+	paths := map[string]map[string]json.RawMessage{
+		"/x/a": {"get": nil, "post": nil, "parameters": nil},
+		"/x/b": {"delete": nil},
+		"/y":   {"get": nil},
+		"/z":   {"$ref": nil},
+	}
+	t.Run("unchanged", func(t *testing.T) {
+		b, changed := getRoutePaths(paths, models.Route{StripPath: true}, "/x", "/")
+		if changed {
+			t.Error("expected unchanged")
+		}
+		if !reflect.DeepEqual(paths, b) {
+			t.Errorf("expected %v, got %v", paths, b)
+		}
+	})
+	t.Run("methods", func(t *testing.T) {
+		b, changed := getRoutePaths(paths, models.Route{Methods: []string{"GET"}, StripPath: true}, "/x", "/")
+		if !changed {
+			t.Error("expected changed")
+		}
+		a := []string{"/x/a", "/y", "/z"}
+		if keys := getPathKeys(b); !reflect.DeepEqual(a, keys) {
+			t.Errorf("expected %v, got %v", a, keys)
+		}
+		if _, ok := b["/x/a"]["post"]; ok {
+			t.Error("unexpected post operation")
+		}
+		if _, ok := b["/x/a"]["parameters"]; !ok {
+			t.Error("expected parameters")
+		}
+	})
+	t.Run("no strip path", func(t *testing.T) {
+		b, changed := getRoutePaths(paths, models.Route{}, "/x", "/")
+		if !changed {
+			t.Error("expected changed")
+		}
+		a := []string{"/x/a", "/x/b"}
+		if keys := getPathKeys(b); !reflect.DeepEqual(a, keys) {
+			t.Errorf("expected %v, got %v", a, keys)
+		}
+	})
+	t.Run("no strip path with base path", func(t *testing.T) {
+		b, changed := getRoutePaths(paths, models.Route{}, "/api/x", "/api")
+		if !changed {
+			t.Error("expected changed")
+		}
+		a := []string{"/x/a", "/x/b"}
+		if keys := getPathKeys(b); !reflect.DeepEqual(a, keys) {
+			t.Errorf("expected %v, got %v", a, keys)
+		}
+		if b, _ = getRoutePaths(paths, models.Route{}, "/x", "/api"); len(b) != 0 {
+			t.Errorf("expected no paths, got %v", getPathKeys(b))
+		}
+	})
+}
+
+func Test_getRouteBasePath(t *testing.T) {
+	tests := []struct {
+		route       models.Route
+		extPath     string
+		docBasePath string
+		expected    string
+	}{
+		{models.Route{StripPath: true}, "/x", "/", "/x"},
+		{models.Route{StripPath: true}, "/x", "/api", "/x"},
+		{models.Route{}, "/x", "/", "/"},
+		{models.Route{}, "/api/x", "/api", "/api"},
+	}
+	for _, tc := range tests {
+		if b := getRouteBasePath(tc.route, tc.extPath, tc.docBasePath); b != tc.expected {
+			t.Errorf("%+v '%s' '%s': expected '%s', got '%s'", tc.route, tc.extPath, tc.docBasePath, tc.expected, b)
+		}
+	}
+}
+
+func Test_getDocBasePath(t *testing.T) {
+	tests := []struct {
+		doc      map[string]json.RawMessage
+		expected string
+	}{
+		{map[string]json.RawMessage{"swagger": []byte(`"2.0"`), "basePath": []byte(`"/api"`)}, "/api"},
+		{map[string]json.RawMessage{"swagger": []byte(`"2.0"`)}, "/"},
+		{map[string]json.RawMessage{"openapi": []byte(`"3.0.0"`), "info": []byte(`{}`), "paths": []byte(`{}`), "servers": []byte(`[{"url":"http://test:8080/api"}]`)}, "/api"},
+		{map[string]json.RawMessage{"openapi": []byte(`"3.0.0"`), "info": []byte(`{}`), "paths": []byte(`{}`)}, "/"},
+	}
+	for i, tc := range tests {
+		if b := getDocBasePath(tc.doc); b != tc.expected {
+			t.Errorf("%d: expected '%s', got '%s'", i, tc.expected, b)
+		}
+	}
+}
+
+func getPathKeys(paths map[string]map[string]json.RawMessage) []string {
+	var keys []string
+	for key := range paths {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
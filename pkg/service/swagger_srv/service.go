@@ -30,39 +30,67 @@ import (
 	"github.com/SENERGY-Platform/go-service-base/struct-logger/attributes"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 const (
-	basePathArgKey    = "base-path"
-	routeArgKey       = "route"
-	titleArgKey       = "title"
-	versionArgKey     = "version"
-	descriptionArgKey = "description"
+	basePathArgKey     = "base-path"
+	routeArgKey        = "route"
+	titleArgKey        = "title"
+	versionArgKey      = "version"
+	descriptionArgKey  = "description"
+	formatArgKey       = "format"
+	docPathArgKey      = "doc-path"
+	workspaceArgKey    = "workspace"
+	etagArgKey         = "etag"
+	lastModifiedArgKey = "last-modified"
+	hashArgKey         = "hash"
+	configHashArgKey   = "config-hash"
+	lastFetchedArgKey  = "last-fetched"
+	lastAttemptArgKey  = "last-attempt"
+	lastErrorArgKey    = "last-error"
+	missingSinceArgKey = "missing-since"
 )
 
 const routeDelimiter = "|"
 
 type Service struct {
+	ctx           context.Context
 	storageHdl    StorageHandler
 	discoveryHdl  DiscoveryHandler
 	docClt        doc_clt.ClientItf
 	ladonClt      ladon_clt.ClientItf
+	reportHdl     ReportHandler
 	timeout       time.Duration
 	apiGtwHost    string
 	adminRoleName string
+	procOpts      ProcurementOptions
+	hostLimiter   *hostLimiter
+	failed        map[string]models.Service
+	failedMu      sync.RWMutex
+	running       atomic.Bool
+	jobs          map[string]*job
+	jobsMu        sync.RWMutex
+	serviceLocks  *keyLock
 	mu            sync.Mutex
 }
 
-func New(storageHdl StorageHandler, discoveryHdl DiscoveryHandler, docClt doc_clt.ClientItf, ladonClt ladon_clt.ClientItf, timeout time.Duration, apiGtwHost string, adminRoleName string) *Service {
+func New(ctx context.Context, storageHdl StorageHandler, discoveryHdl DiscoveryHandler, docClt doc_clt.ClientItf, ladonClt ladon_clt.ClientItf, reportHdl ReportHandler, timeout time.Duration, apiGtwHost string, adminRoleName string, procOpts ProcurementOptions) *Service {
 	return &Service{
+		ctx:           ctx,
 		storageHdl:    storageHdl,
 		discoveryHdl:  discoveryHdl,
 		docClt:        docClt,
 		ladonClt:      ladonClt,
+		reportHdl:     reportHdl,
 		timeout:       timeout,
 		apiGtwHost:    apiGtwHost,
 		adminRoleName: adminRoleName,
+		procOpts:      procOpts,
+		hostLimiter:   newHostLimiter(procOpts.HostRateInterval),
+		jobs:          make(map[string]*job),
+		serviceLocks:  newKeyLock(),
 	}
 }
 
@@ -127,9 +155,14 @@ func (s *Service) SwaggerGetDoc(ctx context.Context, id string, userToken string
 		logger.Error("reading doc failed", slog_attr.IDKey, id, attributes.ErrorKey, err.Error(), slog_attr.RequestIDKey, reqID)
 		return nil, err
 	}
+	return s.prepareDoc(ctx, id, rawDoc, userToken, userRoles)
+}
+
+func (s *Service) prepareDoc(ctx context.Context, id string, rawDoc []byte, userToken string, userRoles []string) ([]byte, error) {
+	reqID := util.GetReqID(ctx)
 	logger.Debug("unmarshalling doc", slog_attr.IDKey, id, slog_attr.RequestIDKey, reqID)
 	var tmp map[string]json.RawMessage
-	if err = json.Unmarshal(rawDoc, &tmp); err != nil {
+	if err := json.Unmarshal(rawDoc, &tmp); err != nil {
 		logger.Error("unmarshalling doc failed", slog_attr.IDKey, id, attributes.ErrorKey, err.Error(), slog_attr.RequestIDKey, reqID)
 		return nil, lib_models.NewInternalError(err)
 	}
@@ -261,7 +294,8 @@ func getRoutes(args [][2]string) (map[string][]string, error) {
 
 func newSwaggerItem(sd models.StorageData) lib_models.SwaggerItem {
 	si := lib_models.SwaggerItem{
-		ID: sd.ID,
+		ID:             sd.ID,
+		PinnedRevision: sd.PinnedRevision,
 	}
 	for _, arg := range sd.Args {
 		switch arg[0] {
@@ -273,6 +307,26 @@ func newSwaggerItem(sd models.StorageData) lib_models.SwaggerItem {
 			si.Description = arg[1]
 		case basePathArgKey:
 			si.BasePath = arg[1]
+		case formatArgKey:
+			si.Format = arg[1]
+		case workspaceArgKey:
+			si.Workspace = arg[1]
+		}
+	}
+	statusArgs := sd.Args
+	if sd.LatestArgs != nil {
+		statusArgs = sd.LatestArgs
+	}
+	for _, arg := range statusArgs {
+		switch arg[0] {
+		case lastFetchedArgKey:
+			si.LastFetched = parseTimeArg(arg[1])
+		case lastAttemptArgKey:
+			si.LastAttempt = parseTimeArg(arg[1])
+		case lastErrorArgKey:
+			si.LastError = arg[1]
+		case missingSinceArgKey:
+			si.MissingSince = parseTimeArg(arg[1])
 		}
 	}
 	return si
@@ -0,0 +1,89 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package swagger_srv
+
+import (
+	"context"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util/slog_attr"
+	"github.com/SENERGY-Platform/go-service-base/struct-logger/attributes"
+	"slices"
+	"time"
+)
+
+var statusArgKeys = []string{lastFetchedArgKey, lastAttemptArgKey, lastErrorArgKey, missingSinceArgKey}
+
+func (s *Service) updateServiceStatus(ctx context.Context, service models.Service, storedItems map[string]models.StorageData, errText string) {
+	for _, extPath := range service.ExtPaths {
+		s.updateStatus(ctx, storedItems[getStorageID(service.ID, extPath)], errText)
+	}
+}
+
+func (s *Service) updateStatus(ctx context.Context, storedItem models.StorageData, errText string) {
+	if storedItem.ID == "" {
+		return
+	}
+	if err := s.storageHdl.SetArgs(ctx, storedItem.ID, setStatusArgs(storedItem.Args, time.Now(), errText)); err != nil {
+		logger.Error("updating doc status failed", slog_attr.IDKey, storedItem.ID, attributes.ErrorKey, err, slog_attr.RequestIDKey, util.GetReqID(ctx))
+	}
+}
+
+func setStatusArgs(args [][2]string, t time.Time, errText string) [][2]string {
+	lastFetched, _ := getArg(args, lastFetchedArgKey)
+	ts := t.UTC().Format(time.RFC3339)
+	if errText == "" {
+		lastFetched = ts
+	}
+	args = removeStatusArgs(args)
+	if lastFetched != "" {
+		args = append(args, [2]string{lastFetchedArgKey, lastFetched})
+	}
+	args = append(args, [2]string{lastAttemptArgKey, ts})
+	if errText != "" {
+		args = append(args, [2]string{lastErrorArgKey, errText})
+	}
+	return args
+}
+
+func getStatusArgs(args [][2]string) [][2]string {
+	var newArgs [][2]string
+	for _, arg := range args {
+		if slices.Contains(statusArgKeys, arg[0]) {
+			newArgs = append(newArgs, arg)
+		}
+	}
+	return newArgs
+}
+
+func removeStatusArgs(args [][2]string) [][2]string {
+	var newArgs [][2]string
+	for _, arg := range args {
+		if !slices.Contains(statusArgKeys, arg[0]) {
+			newArgs = append(newArgs, arg)
+		}
+	}
+	return newArgs
+}
+
+func parseTimeArg(val string) *time.Time {
+	t, err := time.Parse(time.RFC3339, val)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
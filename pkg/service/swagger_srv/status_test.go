@@ -0,0 +1,167 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package swagger_srv
+
+import (
+	"context"
+	"errors"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/components/doc_clt"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util"
+	struct_logger "github.com/SENERGY-Platform/go-service-base/struct-logger"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_setStatusArgs(t *testing.T) {
+	t1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+	t.Run("fetched", func(t *testing.T) {
+		a := [][2]string{{titleArgKey, "a"}, {lastFetchedArgKey, "2025-01-01T01:00:00Z"}, {lastAttemptArgKey, "2025-01-01T01:00:00Z"}}
+		b := setStatusArgs([][2]string{{titleArgKey, "a"}, {lastFetchedArgKey, "2025-01-01T00:00:00Z"}, {lastErrorArgKey, "test"}, {missingSinceArgKey, "2025-01-01T00:00:00Z"}}, t2, "")
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("expected %v, got %v", a, b)
+		}
+	})
+	t.Run("error", func(t *testing.T) {
+		args := [][2]string{{titleArgKey, "a"}, {lastFetchedArgKey, t1.Format(time.RFC3339)}, {lastAttemptArgKey, t1.Format(time.RFC3339)}}
+		a := [][2]string{{titleArgKey, "a"}, {lastFetchedArgKey, "2025-01-01T00:00:00Z"}, {lastAttemptArgKey, "2025-01-01T01:00:00Z"}, {lastErrorArgKey, "test"}}
+		b := setStatusArgs(args, t2, "test")
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("expected %v, got %v", a, b)
+		}
+		if args[1][1] != "2025-01-01T00:00:00Z" || len(args) != 3 {
+			t.Error("input args modified")
+		}
+	})
+	t.Run("error never fetched", func(t *testing.T) {
+		a := [][2]string{{titleArgKey, "a"}, {lastAttemptArgKey, "2025-01-01T01:00:00Z"}, {lastErrorArgKey, "test"}}
+		b := setStatusArgs([][2]string{{titleArgKey, "a"}}, t2, "test")
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("expected %v, got %v", a, b)
+		}
+	})
+}
+
+func TestHandler_RefreshStorageStatus(t *testing.T) {
+	validDoc, err := os.ReadFile("test/swagger.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	storageHdl := &storageHdlMock{}
+	docClt := &docCltMock{
+		Docs: map[string][]byte{
+			"ph0/doc": validDoc,
+		},
+	}
+	discoveryHdl := &discoveryHdlMock{
+		Services: map[string]models.Service{
+			"ph0": {ID: "ph0", Host: "h", Port: 0, Protocol: "p", ExtPaths: []string{"/t"}},
+		},
+	}
+	util.InitLogger(struct_logger.Config{}, os.Stderr, "", "")
+	InitLogger()
+	srv := New(context.Background(), storageHdl, discoveryHdl, docClt, nil, &reportHdlMock{}, 0, "test.test", "admin", ProcurementOptions{DocPaths: []string{"/doc"}, MissingGracePeriod: time.Hour})
+	if err = srv.SwaggerRefreshDocs(context.Background(), false); err != nil {
+		t.Fatal(err)
+	}
+	lastFetched, ok := getArg(storageHdl.Items["ph0_t"].Args, lastFetchedArgKey)
+	if !ok {
+		t.Fatal("expected last fetched arg")
+	}
+	if _, ok = getArg(storageHdl.Items["ph0_t"].Args, lastAttemptArgKey); !ok {
+		t.Error("expected last attempt arg")
+	}
+	t.Run("fetch error", func(t *testing.T) {
+		docClt.Errs = map[string]error{"ph0/doc": doc_clt.NewResponseError(503, errors.New("unavailable"))}
+		defer func() { docClt.Errs = nil }()
+		if err = srv.SwaggerRefreshDocs(context.Background(), false); err != nil {
+			t.Fatal(err)
+		}
+		items, err := srv.SwaggerListStorage(context.Background(), "", []string{"admin"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != 1 {
+			t.Fatalf("expected 1 item, got %d", len(items))
+		}
+		if items[0].LastError == "" || items[0].LastFetched == nil || items[0].LastAttempt == nil {
+			t.Errorf("unexpected item %+v", items[0])
+		}
+		if items[0].LastFetched.Format(time.RFC3339) != lastFetched {
+			t.Errorf("expected last fetched %s, got %s", lastFetched, items[0].LastFetched)
+		}
+	})
+	t.Run("missing", func(t *testing.T) {
+		services := discoveryHdl.Services
+		discoveryHdl.Services = map[string]models.Service{}
+		if err = srv.SwaggerRefreshDocs(context.Background(), false); err != nil {
+			t.Fatal(err)
+		}
+		discoveryHdl.Services = services
+		if _, ok := getArg(storageHdl.Items["ph0_t"].Args, missingSinceArgKey); !ok {
+			t.Error("expected missing since arg")
+		}
+	})
+	t.Run("recovered", func(t *testing.T) {
+		if err = srv.SwaggerRefreshDocs(context.Background(), false); err != nil {
+			t.Fatal(err)
+		}
+		items, err := srv.SwaggerListStorage(context.Background(), "", []string{"admin"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != 1 || items[0].LastError != "" || items[0].MissingSince != nil {
+			t.Errorf("unexpected items %+v", items)
+		}
+	})
+}
+
+func TestHandler_cleanOldServicesGracePeriod(t *testing.T) {
+	now := time.Now().UTC()
+	sHdl := &storageHdlMock{}
+	for id, args := range map[string][][2]string{
+		"id-1": nil,
+		"id-2": {{missingSinceArgKey, now.Add(-time.Minute * 10).Format(time.RFC3339)}},
+		"id-3": {{missingSinceArgKey, now.Add(-time.Hour * 2).Format(time.RFC3339)}},
+		"id-4": {{missingSinceArgKey, "test"}},
+	} {
+		if err := sHdl.Write(context.Background(), id, args, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv := New(context.Background(), sHdl, nil, nil, nil, nil, 0, "", "", ProcurementOptions{MissingGracePeriod: time.Hour})
+	if _, err := srv.cleanOldServices(context.Background(), map[string]models.Service{}, false); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"id-1", "id-2", "id-4"} {
+		item, ok := sHdl.Items[id]
+		if !ok {
+			t.Errorf("expected '%s'", id)
+			continue
+		}
+		v, _ := getArg(item.Args, missingSinceArgKey)
+		if parseTimeArg(v) == nil {
+			t.Errorf("expected valid missing since arg for '%s', got '%s'", id, v)
+		}
+	}
+	if _, ok := sHdl.Items["id-3"]; ok {
+		t.Error("expected 'id-3' to be removed")
+	}
+}
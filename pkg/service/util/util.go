@@ -16,7 +16,12 @@
 
 package util
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"errors"
+	lib_models "github.com/SENERGY-Platform/api-docs-provider/lib/models"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/util/doc_diff"
+)
 
 func CheckForKeys(doc map[string]json.RawMessage, keys []string) bool {
 	c := 0
@@ -27,3 +32,29 @@ func CheckForKeys(doc map[string]json.RawMessage, keys []string) bool {
 	}
 	return c == len(keys)
 }
+
+func GetDiffRevisions(revisions []lib_models.DocRevision, from, to string) (string, string, error) {
+	if to == "" {
+		to = revisions[0].ID
+	}
+	if from == "" {
+		if len(revisions) < 2 {
+			return "", "", lib_models.NewInvalidInputError(errors.New("no previous revision"))
+		}
+		from = revisions[1].ID
+	}
+	return from, to, nil
+}
+
+func NewDocDiff(from, to string, fromDoc, toDoc []byte) (lib_models.DocDiff, error) {
+	changes, err := doc_diff.Diff(fromDoc, toDoc)
+	if err != nil {
+		return lib_models.DocDiff{}, lib_models.NewInvalidInputError(err)
+	}
+	return lib_models.DocDiff{
+		From:     from,
+		To:       to,
+		Breaking: doc_diff.IsBreaking(changes),
+		Changes:  changes,
+	}, nil
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package doc_diff
+
+import (
+	"fmt"
+	lib_models "github.com/SENERGY-Platform/api-docs-provider/lib/models"
+	"reflect"
+)
+
+var asyncapiV2Operations = []string{"publish", "subscribe"}
+
+func (d *differ) diffAsyncapi(a, b map[string]any) {
+	d.diffKeys([]string{"channels"}, getMap(a, "channels"), getMap(b, "channels"), "channel", true, func(location []string, a, b any) {
+		d.diffChannel(location, asMap(a), asMap(b))
+	})
+	d.diffKeys([]string{"operations"}, getMap(a, "operations"), getMap(b, "operations"), "operation", true, func(location []string, a, b any) {
+		d.diffAsyncapiOperation(location, asMap(a), asMap(b))
+	})
+	d.diffKeys([]string{"messages"}, getMap(a, "components", "messages"), getMap(b, "components", "messages"), "message", true, func(location []string, a, b any) {
+		d.diffMessage(location, asMap(a), asMap(b))
+	})
+	d.diffSchemas([]string{"schemas"}, getMap(a, "components", "schemas"), getMap(b, "components", "schemas"))
+}
+
+func (d *differ) diffChannel(location []string, a, b map[string]any) {
+	for _, op := range asyncapiV2Operations {
+		opA, okA := a[op]
+		opB, okB := b[op]
+		switch {
+		case okA && !okB:
+			d.add(lib_models.DocChangeRemoved, true, "operation removed", appendLoc(location, op))
+		case !okA && okB:
+			d.add(lib_models.DocChangeAdded, false, "operation added", appendLoc(location, op))
+		case okA && okB:
+			d.diffMessage(appendLoc(location, op, "message"), getMap(asMap(opA), "message"), getMap(asMap(opB), "message"))
+		}
+	}
+	d.diffKeys(appendLoc(location, "messages"), getMap(a, "messages"), getMap(b, "messages"), "message", true, func(location []string, a, b any) {
+		d.diffMessage(location, asMap(a), asMap(b))
+	})
+}
+
+func (d *differ) diffAsyncapiOperation(location []string, a, b map[string]any) {
+	if actionA, actionB := getString(a, "action"), getString(b, "action"); actionA != actionB {
+		d.add(lib_models.DocChangeChanged, true, fmt.Sprintf("action changed from '%s' to '%s'", actionA, actionB), location)
+	}
+	if refA, refB := getString(getMap(a, "channel"), "$ref"), getString(getMap(b, "channel"), "$ref"); refA != refB {
+		d.add(lib_models.DocChangeChanged, true, fmt.Sprintf("channel changed from '%s' to '%s'", refA, refB), location)
+	}
+}
+
+func (d *differ) diffMessage(location []string, a, b map[string]any) {
+	if a == nil && b == nil {
+		return
+	}
+	refA, refB := getString(a, "$ref"), getString(b, "$ref")
+	if refA != refB {
+		d.add(lib_models.DocChangeChanged, true, fmt.Sprintf("message reference changed from '%s' to '%s'", refA, refB), location)
+		return
+	}
+	if refA != "" {
+		return
+	}
+	if !reflect.DeepEqual(a["oneOf"], b["oneOf"]) {
+		d.add(lib_models.DocChangeChanged, true, "oneOf changed", appendLoc(location, "oneOf"))
+	}
+	d.diffSchema(appendLoc(location, "payload"), getMap(a, "payload"), getMap(b, "payload"), directionNone)
+}
@@ -0,0 +1,169 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package doc_diff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	lib_models "github.com/SENERGY-Platform/api-docs-provider/lib/models"
+	"slices"
+	"strings"
+)
+
+const (
+	kindOpenApi  = "openapi"
+	kindAsyncapi = "asyncapi"
+)
+
+const (
+	directionNone = iota
+	directionRequest
+	directionResponse
+)
+
+type differ struct {
+	changes []lib_models.DocChange
+}
+
+func Diff(from, to []byte) ([]lib_models.DocChange, error) {
+	var a, b map[string]any
+	if err := json.Unmarshal(from, &a); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(to, &b); err != nil {
+		return nil, err
+	}
+	kind := getKind(a)
+	if kind == "" {
+		return nil, errors.New("unsupported doc format")
+	}
+	if kind != getKind(b) {
+		return nil, errors.New("doc formats do not match")
+	}
+	d := &differ{}
+	switch kind {
+	case kindOpenApi:
+		d.diffOpenApi(a, b)
+	case kindAsyncapi:
+		d.diffAsyncapi(a, b)
+	}
+	slices.SortStableFunc(d.changes, func(a, b lib_models.DocChange) int {
+		return strings.Compare(a.Location, b.Location)
+	})
+	return d.changes, nil
+}
+
+func Summarize(changes []lib_models.DocChange) lib_models.DocChangeSummary {
+	var summary lib_models.DocChangeSummary
+	for _, change := range changes {
+		if change.Breaking {
+			summary.Breaking++
+		} else {
+			summary.NonBreaking++
+		}
+	}
+	return summary
+}
+
+func IsBreaking(changes []lib_models.DocChange) bool {
+	return slices.ContainsFunc(changes, func(change lib_models.DocChange) bool {
+		return change.Breaking
+	})
+}
+
+func (d *differ) add(changeType string, breaking bool, description string, location []string) {
+	d.changes = append(d.changes, lib_models.DocChange{
+		Type:        changeType,
+		Location:    strings.Join(location, "."),
+		Description: description,
+		Breaking:    breaking,
+	})
+}
+
+func (d *differ) diffKeys(location []string, a, b map[string]any, name string, removedBreaking bool, diffFunc func(location []string, a, b any)) {
+	for _, key := range sortedKeys(a) {
+		vb, ok := b[key]
+		if !ok {
+			d.add(lib_models.DocChangeRemoved, removedBreaking, name+" removed", appendLoc(location, key))
+			continue
+		}
+		if diffFunc != nil {
+			diffFunc(appendLoc(location, key), a[key], vb)
+		}
+	}
+	for _, key := range sortedKeys(b) {
+		if _, ok := a[key]; !ok {
+			d.add(lib_models.DocChangeAdded, false, name+" added", appendLoc(location, key))
+		}
+	}
+}
+
+func getKind(doc map[string]any) string {
+	if _, ok := doc["asyncapi"]; ok {
+		return kindAsyncapi
+	}
+	if _, ok := doc["openapi"]; ok {
+		return kindOpenApi
+	}
+	if _, ok := doc["swagger"]; ok {
+		return kindOpenApi
+	}
+	return ""
+}
+
+func getMap(m map[string]any, keys ...string) map[string]any {
+	for _, key := range keys {
+		if m == nil {
+			return nil
+		}
+		m, _ = m[key].(map[string]any)
+	}
+	return m
+}
+
+func asMap(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return m
+}
+
+func getString(m map[string]any, key string) string {
+	if m == nil {
+		return ""
+	}
+	switch v := m[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func appendLoc(location []string, elems ...string) []string {
+	return append(slices.Clone(location), elems...)
+}
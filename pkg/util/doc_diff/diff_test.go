@@ -0,0 +1,271 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package doc_diff
+
+import (
+	lib_models "github.com/SENERGY-Platform/api-docs-provider/lib/models"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	t.Run("swagger", func(t *testing.T) {
+		from := `{
+			"swagger": "2.0",
+			"paths": {
+				"/a": {
+					"get": {
+						"parameters": [{"name": "q", "in": "query", "type": "string"}, {"name": "x", "in": "query", "type": "string"}],
+						"responses": {"200": {"schema": {"$ref": "#/definitions/A"}}, "404": {}}
+					},
+					"delete": {}
+				},
+				"/b": {"get": {}}
+			},
+			"definitions": {
+				"A": {"type": "object", "required": ["id"], "properties": {"id": {"type": "string"}, "name": {"type": "string"}}},
+				"B": {"type": "object"}
+			}
+		}`
+		to := `{
+			"swagger": "2.0",
+			"paths": {
+				"/a": {
+					"get": {
+						"parameters": [{"name": "q", "in": "query", "type": "integer"}, {"name": "y", "in": "query", "type": "string", "required": true}],
+						"responses": {"200": {"schema": {"$ref": "#/definitions/A"}}}
+					},
+					"post": {"parameters": [{"name": "body", "in": "body", "schema": {"type": "object"}}]}
+				},
+				"/c": {"get": {}}
+			},
+			"definitions": {
+				"A": {"type": "object", "required": ["id"], "properties": {"id": {"type": "string"}, "tags": {"type": "array", "items": {"type": "string"}}}}
+			}
+		}`
+		a := []lib_models.DocChange{
+			{Type: lib_models.DocChangeRemoved, Location: "paths./a.delete", Description: "operation removed", Breaking: true},
+			{Type: lib_models.DocChangeChanged, Location: "paths./a.get.parameters.query:q", Description: "type changed from 'string' to 'integer'", Breaking: true},
+			{Type: lib_models.DocChangeRemoved, Location: "paths./a.get.parameters.query:x", Description: "parameter removed", Breaking: true},
+			{Type: lib_models.DocChangeAdded, Location: "paths./a.get.parameters.query:y", Description: "parameter added", Breaking: true},
+			{Type: lib_models.DocChangeRemoved, Location: "paths./a.get.responses.404", Description: "response removed", Breaking: true},
+			{Type: lib_models.DocChangeAdded, Location: "paths./a.post", Description: "operation added", Breaking: false},
+			{Type: lib_models.DocChangeRemoved, Location: "paths./b", Description: "path removed", Breaking: true},
+			{Type: lib_models.DocChangeAdded, Location: "paths./c", Description: "path added", Breaking: false},
+			{Type: lib_models.DocChangeRemoved, Location: "schemas.A.properties.name", Description: "property removed", Breaking: true},
+			{Type: lib_models.DocChangeAdded, Location: "schemas.A.properties.tags", Description: "property added", Breaking: false},
+			{Type: lib_models.DocChangeRemoved, Location: "schemas.B", Description: "schema removed", Breaking: true},
+		}
+		b, err := Diff([]byte(from), []byte(to))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("expected %+v, got %+v", a, b)
+		}
+		if !IsBreaking(b) {
+			t.Error("expected breaking")
+		}
+		s := Summarize(b)
+		if s.Breaking != 8 || s.NonBreaking != 3 {
+			t.Errorf("unexpected summary %+v", s)
+		}
+	})
+	t.Run("openapi", func(t *testing.T) {
+		from := `{
+			"openapi": "3.0.0",
+			"paths": {
+				"/a": {
+					"post": {
+						"requestBody": {"content": {"application/json": {"schema": {"type": "object", "properties": {"a": {"type": "string"}}}}}},
+						"responses": {"200": {"content": {"application/json": {"schema": {"type": "object", "required": ["x"], "properties": {"x": {"type": "string"}, "y": {"type": "string", "enum": ["a", "b"]}}}}}}}
+					}
+				}
+			}
+		}`
+		to := `{
+			"openapi": "3.0.0",
+			"paths": {
+				"/a": {
+					"post": {
+						"requestBody": {"required": true, "content": {"application/json": {"schema": {"type": "object", "required": ["a"], "properties": {"a": {"type": "string"}, "b": {"type": "string"}}}}}},
+						"responses": {"200": {"content": {"application/json": {"schema": {"type": "object", "properties": {"x": {"type": "string"}, "y": {"type": "string", "enum": ["a", "b", "c"]}}}}}}, "201": {}}
+					}
+				}
+			}
+		}`
+		a := []lib_models.DocChange{
+			{Type: lib_models.DocChangeChanged, Location: "paths./a.post.requestBody", Description: "request body became required", Breaking: true},
+			{Type: lib_models.DocChangeChanged, Location: "paths./a.post.requestBody.content.application/json.schema.properties.a", Description: "property became required", Breaking: true},
+			{Type: lib_models.DocChangeAdded, Location: "paths./a.post.requestBody.content.application/json.schema.properties.b", Description: "property added", Breaking: false},
+			{Type: lib_models.DocChangeChanged, Location: "paths./a.post.responses.200.content.application/json.schema.properties.x", Description: "property became optional", Breaking: true},
+			{Type: lib_models.DocChangeChanged, Location: "paths./a.post.responses.200.content.application/json.schema.properties.y.enum", Description: "enum values added: [c]", Breaking: true},
+			{Type: lib_models.DocChangeAdded, Location: "paths./a.post.responses.201", Description: "response added", Breaking: false},
+		}
+		b, err := Diff([]byte(from), []byte(to))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("expected %+v, got %+v", a, b)
+		}
+	})
+	t.Run("asyncapi", func(t *testing.T) {
+		from := `{
+			"asyncapi": "2.6.0",
+			"channels": {
+				"a": {"publish": {"message": {"payload": {"type": "object", "properties": {"v": {"type": "number"}}}}}, "subscribe": {}},
+				"b": {}
+			},
+			"components": {"schemas": {"S": {"type": "string"}}}
+		}`
+		to := `{
+			"asyncapi": "2.6.0",
+			"channels": {
+				"a": {"publish": {"message": {"payload": {"type": "object", "properties": {"v": {"type": "string"}}}}}},
+				"c": {}
+			},
+			"components": {"schemas": {"S": {"type": "string"}, "T": {"type": "string"}}}
+		}`
+		a := []lib_models.DocChange{
+			{Type: lib_models.DocChangeChanged, Location: "channels.a.publish.message.payload.properties.v", Description: "type changed from 'number' to 'string'", Breaking: true},
+			{Type: lib_models.DocChangeRemoved, Location: "channels.a.subscribe", Description: "operation removed", Breaking: true},
+			{Type: lib_models.DocChangeRemoved, Location: "channels.b", Description: "channel removed", Breaking: true},
+			{Type: lib_models.DocChangeAdded, Location: "channels.c", Description: "channel added", Breaking: false},
+			{Type: lib_models.DocChangeAdded, Location: "schemas.T", Description: "schema added", Breaking: false},
+		}
+		b, err := Diff([]byte(from), []byte(to))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("expected %+v, got %+v", a, b)
+		}
+	})
+	t.Run("asyncapi v3", func(t *testing.T) {
+		from := `{"asyncapi": "3.0.0", "operations": {"o": {"action": "send", "channel": {"$ref": "#/channels/a"}}, "p": {"action": "send"}}}`
+		to := `{"asyncapi": "3.0.0", "operations": {"o": {"action": "receive", "channel": {"$ref": "#/channels/a"}}}}`
+		a := []lib_models.DocChange{
+			{Type: lib_models.DocChangeChanged, Location: "operations.o", Description: "action changed from 'send' to 'receive'", Breaking: true},
+			{Type: lib_models.DocChangeRemoved, Location: "operations.p", Description: "operation removed", Breaking: true},
+		}
+		b, err := Diff([]byte(from), []byte(to))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("expected %+v, got %+v", a, b)
+		}
+	})
+	t.Run("additional properties", func(t *testing.T) {
+		from := `{
+			"openapi": "3.0.0",
+			"components": {"schemas": {
+				"A": {"type": "object", "additionalProperties": {"type": "string"}},
+				"B": {"type": "object", "additionalProperties": true},
+				"C": {"type": "object"},
+				"D": {"type": "object", "additionalProperties": {"type": "string"}},
+				"E": {"type": "object", "additionalProperties": false}
+			}}
+		}`
+		to := `{
+			"openapi": "3.0.0",
+			"components": {"schemas": {
+				"A": {"type": "object", "additionalProperties": false},
+				"B": {"type": "object"},
+				"C": {"type": "object", "additionalProperties": {"type": "string"}},
+				"D": {"type": "object", "additionalProperties": {"type": "integer"}},
+				"E": {"type": "object", "additionalProperties": false}
+			}}
+		}`
+		a := []lib_models.DocChange{
+			{Type: lib_models.DocChangeChanged, Location: "schemas.A.additionalProperties", Description: "additionalProperties changed from schema to 'false'", Breaking: true},
+			{Type: lib_models.DocChangeRemoved, Location: "schemas.B.additionalProperties", Description: "additionalProperties 'true' removed", Breaking: true},
+			{Type: lib_models.DocChangeAdded, Location: "schemas.C.additionalProperties", Description: "additionalProperties set to schema", Breaking: true},
+			{Type: lib_models.DocChangeChanged, Location: "schemas.D.additionalProperties", Description: "type changed from 'string' to 'integer'", Breaking: true},
+		}
+		b, err := Diff([]byte(from), []byte(to))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("expected %+v, got %+v", a, b)
+		}
+	})
+	t.Run("parameter references", func(t *testing.T) {
+		from := `{
+			"openapi": "3.0.0",
+			"paths": {
+				"/a": {
+					"get": {"parameters": [{"name": "limit", "in": "query", "schema": {"type": "integer"}}, {"$ref": "#/components/parameters/Offset"}, {"name": "id", "in": "header", "schema": {"type": "string"}}]}
+				}
+			},
+			"components": {"parameters": {"Offset": {"name": "offset", "in": "query", "schema": {"type": "integer"}}}}
+		}`
+		to := `{
+			"openapi": "3.0.0",
+			"paths": {
+				"/a": {
+					"get": {"parameters": [{"$ref": "#/components/parameters/Limit"}, {"name": "offset", "in": "query", "schema": {"type": "integer"}}, {"$ref": "#/components/parameters/ID"}]}
+				}
+			},
+			"components": {"parameters": {
+				"Limit": {"name": "limit", "in": "query", "schema": {"type": "integer"}},
+				"ID": {"name": "id", "in": "header", "required": true, "schema": {"type": "string"}}
+			}}
+		}`
+		a := []lib_models.DocChange{
+			{Type: lib_models.DocChangeChanged, Location: "paths./a.get.parameters.header:id", Description: "parameter became required", Breaking: true},
+		}
+		b, err := Diff([]byte(from), []byte(to))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("expected %+v, got %+v", a, b)
+		}
+		from = `{"swagger": "2.0", "paths": {"/a": {"get": {"parameters": [{"$ref": "#/parameters/Limit"}]}}}, "parameters": {"Limit": {"name": "limit", "in": "query", "type": "integer"}}}`
+		to = `{"swagger": "2.0", "paths": {"/a": {"get": {"parameters": [{"name": "limit", "in": "query", "type": "integer"}]}}}}`
+		if b, err = Diff([]byte(from), []byte(to)); err != nil {
+			t.Fatal(err)
+		}
+		if len(b) != 0 {
+			t.Errorf("expected no changes, got %+v", b)
+		}
+	})
+	t.Run("unchanged", func(t *testing.T) {
+		doc := []byte(`{"openapi": "3.0.0", "paths": {"/a": {"get": {"responses": {"200": {}}}}}}`)
+		b, err := Diff(doc, doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) != 0 || IsBreaking(b) {
+			t.Errorf("expected no changes, got %v", b)
+		}
+	})
+	t.Run("error", func(t *testing.T) {
+		if _, err := Diff([]byte(`{"openapi": "3.0.0"}`), []byte(`{"asyncapi": "3.0.0"}`)); err == nil {
+			t.Error("expected error")
+		}
+		if _, err := Diff([]byte(`{}`), []byte(`{}`)); err == nil {
+			t.Error("expected error")
+		}
+		if _, err := Diff([]byte(`test`), []byte(`{}`)); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
@@ -0,0 +1,193 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package doc_diff
+
+import (
+	lib_models "github.com/SENERGY-Platform/api-docs-provider/lib/models"
+	"strings"
+)
+
+var httpMethods = []string{
+	"get",
+	"put",
+	"post",
+	"delete",
+	"options",
+	"head",
+	"patch",
+	"trace",
+}
+
+var parameterSchemaKeys = []string{"type", "format", "items", "enum"}
+
+var parameterRefPrefixes = []string{"#/components/parameters/", "#/parameters/"}
+
+func (d *differ) diffOpenApi(a, b map[string]any) {
+	paramDefsA, paramDefsB := getOpenApiParameters(a), getOpenApiParameters(b)
+	d.diffKeys([]string{"paths"}, getMap(a, "paths"), getMap(b, "paths"), "path", true, func(location []string, a, b any) {
+		d.diffPathItem(location, asMap(a), asMap(b), paramDefsA, paramDefsB)
+	})
+	d.diffSchemas([]string{"schemas"}, getOpenApiSchemas(a), getOpenApiSchemas(b))
+}
+
+func (d *differ) diffPathItem(location []string, a, b map[string]any, paramDefsA, paramDefsB map[string]any) {
+	for _, method := range httpMethods {
+		opA, okA := a[method]
+		opB, okB := b[method]
+		switch {
+		case okA && !okB:
+			d.add(lib_models.DocChangeRemoved, true, "operation removed", appendLoc(location, method))
+		case !okA && okB:
+			d.add(lib_models.DocChangeAdded, false, "operation added", appendLoc(location, method))
+		case okA && okB:
+			d.diffOperation(appendLoc(location, method), asMap(opA), asMap(opB), a["parameters"], b["parameters"], paramDefsA, paramDefsB)
+		}
+	}
+}
+
+func (d *differ) diffOperation(location []string, a, b map[string]any, pathParamsA, pathParamsB any, paramDefsA, paramDefsB map[string]any) {
+	paramsA, bodyA := getParameters(pathParamsA, a["parameters"], paramDefsA)
+	paramsB, bodyB := getParameters(pathParamsB, b["parameters"], paramDefsB)
+	for _, key := range sortedKeys(paramsA) {
+		pLocation := appendLoc(location, "parameters", key)
+		pB, ok := paramsB[key]
+		if !ok {
+			d.add(lib_models.DocChangeRemoved, true, "parameter removed", pLocation)
+			continue
+		}
+		pA := asMap(paramsA[key])
+		wasRequired, isRequired := pA["required"] == true, asMap(pB)["required"] == true
+		if !wasRequired && isRequired {
+			d.add(lib_models.DocChangeChanged, true, "parameter became required", pLocation)
+		}
+		if wasRequired && !isRequired {
+			d.add(lib_models.DocChangeChanged, false, "parameter became optional", pLocation)
+		}
+		d.diffSchema(pLocation, getParameterSchema(pA), getParameterSchema(asMap(pB)), directionRequest)
+	}
+	for _, key := range sortedKeys(paramsB) {
+		if _, ok := paramsA[key]; !ok {
+			d.add(lib_models.DocChangeAdded, asMap(paramsB[key])["required"] == true, "parameter added", appendLoc(location, "parameters", key))
+		}
+	}
+	if bodyA == nil {
+		bodyA = asMap(a["requestBody"])
+	}
+	if bodyB == nil {
+		bodyB = asMap(b["requestBody"])
+	}
+	d.diffRequestBody(appendLoc(location, "requestBody"), bodyA, bodyB)
+	d.diffKeys(appendLoc(location, "responses"), getMap(a, "responses"), getMap(b, "responses"), "response", true, func(location []string, a, b any) {
+		d.diffContent(location, asMap(a), asMap(b), directionResponse)
+	})
+}
+
+func (d *differ) diffRequestBody(location []string, a, b map[string]any) {
+	switch {
+	case a == nil && b == nil:
+		return
+	case a == nil:
+		d.add(lib_models.DocChangeAdded, b["required"] == true, "request body added", location)
+		return
+	case b == nil:
+		d.add(lib_models.DocChangeRemoved, true, "request body removed", location)
+		return
+	}
+	if a["required"] != true && b["required"] == true {
+		d.add(lib_models.DocChangeChanged, true, "request body became required", location)
+	}
+	d.diffContent(location, a, b, directionRequest)
+}
+
+func (d *differ) diffContent(location []string, a, b map[string]any, direction int) {
+	contentA, contentB := getMap(a, "content"), getMap(b, "content")
+	if contentA == nil && contentB == nil {
+		d.diffSchema(appendLoc(location, "schema"), asMap(a["schema"]), asMap(b["schema"]), direction)
+		return
+	}
+	d.diffKeys(appendLoc(location, "content"), contentA, contentB, "media type", true, func(location []string, a, b any) {
+		d.diffSchema(appendLoc(location, "schema"), getMap(asMap(a), "schema"), getMap(asMap(b), "schema"), direction)
+	})
+}
+
+func getParameters(pathParams, opParams any, paramDefs map[string]any) (map[string]any, map[string]any) {
+	params := make(map[string]any)
+	var body map[string]any
+	for _, list := range []any{pathParams, opParams} {
+		sl, _ := list.([]any)
+		for _, item := range sl {
+			param := asMap(item)
+			if param == nil {
+				continue
+			}
+			ref := getString(param, "$ref")
+			if ref != "" {
+				if resolved := resolveParameter(ref, paramDefs); resolved != nil {
+					param = resolved
+					ref = ""
+				}
+			}
+			in := getString(param, "in")
+			if in == "body" {
+				body = param
+				continue
+			}
+			key := ref
+			if key == "" {
+				key = in + ":" + getString(param, "name")
+			}
+			params[key] = param
+		}
+	}
+	return params, body
+}
+
+func resolveParameter(ref string, paramDefs map[string]any) map[string]any {
+	for _, prefix := range parameterRefPrefixes {
+		if name, ok := strings.CutPrefix(ref, prefix); ok {
+			return asMap(paramDefs[name])
+		}
+	}
+	return nil
+}
+
+func getParameterSchema(param map[string]any) map[string]any {
+	if schema := getMap(param, "schema"); schema != nil {
+		return schema
+	}
+	schema := make(map[string]any)
+	for _, key := range parameterSchemaKeys {
+		if v, ok := param[key]; ok {
+			schema[key] = v
+		}
+	}
+	return schema
+}
+
+func getOpenApiSchemas(doc map[string]any) map[string]any {
+	if schemas := getMap(doc, "definitions"); schemas != nil {
+		return schemas
+	}
+	return getMap(doc, "components", "schemas")
+}
+
+func getOpenApiParameters(doc map[string]any) map[string]any {
+	if params := getMap(doc, "parameters"); params != nil {
+		return params
+	}
+	return getMap(doc, "components", "parameters")
+}
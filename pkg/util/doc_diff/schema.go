@@ -0,0 +1,166 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package doc_diff
+
+import (
+	"fmt"
+	lib_models "github.com/SENERGY-Platform/api-docs-provider/lib/models"
+	"reflect"
+	"slices"
+)
+
+var schemaCompositionKeys = []string{"allOf", "oneOf", "anyOf", "not"}
+
+func (d *differ) diffSchemas(location []string, a, b map[string]any) {
+	d.diffKeys(location, a, b, "schema", true, func(location []string, a, b any) {
+		d.diffSchema(location, asMap(a), asMap(b), directionNone)
+	})
+}
+
+func (d *differ) diffSchema(location []string, a, b map[string]any, direction int) {
+	if a == nil && b == nil {
+		return
+	}
+	if a == nil || b == nil {
+		if a == nil {
+			d.add(lib_models.DocChangeAdded, direction == directionRequest, "schema added", location)
+		} else {
+			d.add(lib_models.DocChangeRemoved, true, "schema removed", location)
+		}
+		return
+	}
+	refA, refB := getString(a, "$ref"), getString(b, "$ref")
+	if refA != refB {
+		d.add(lib_models.DocChangeChanged, true, fmt.Sprintf("schema reference changed from '%s' to '%s'", refA, refB), location)
+		return
+	}
+	if refA != "" {
+		return
+	}
+	for _, key := range []string{"type", "format"} {
+		if va, vb := getString(a, key), getString(b, key); va != vb {
+			d.add(lib_models.DocChangeChanged, true, fmt.Sprintf("%s changed from '%s' to '%s'", key, va, vb), location)
+			return
+		}
+	}
+	d.diffEnum(location, a["enum"], b["enum"], direction)
+	for _, key := range schemaCompositionKeys {
+		if !reflect.DeepEqual(a[key], b[key]) {
+			d.add(lib_models.DocChangeChanged, true, key+" changed", appendLoc(location, key))
+		}
+	}
+	d.diffProperties(location, a, b, direction)
+	if itemsA, itemsB := asMap(a["items"]), asMap(b["items"]); itemsA != nil || itemsB != nil {
+		d.diffSchema(appendLoc(location, "items"), itemsA, itemsB, direction)
+	}
+	d.diffAdditionalProperties(appendLoc(location, "additionalProperties"), a["additionalProperties"], b["additionalProperties"], direction)
+}
+
+func (d *differ) diffAdditionalProperties(location []string, a, b any, direction int) {
+	if apA, apB := asMap(a), asMap(b); apA != nil && apB != nil {
+		d.diffSchema(location, apA, apB, direction)
+		return
+	}
+	if reflect.DeepEqual(a, b) {
+		return
+	}
+	switch {
+	case a == nil:
+		d.add(lib_models.DocChangeAdded, true, fmt.Sprintf("additionalProperties set to %s", describeAdditionalProperties(b)), location)
+	case b == nil:
+		d.add(lib_models.DocChangeRemoved, true, fmt.Sprintf("additionalProperties %s removed", describeAdditionalProperties(a)), location)
+	default:
+		d.add(lib_models.DocChangeChanged, true, fmt.Sprintf("additionalProperties changed from %s to %s", describeAdditionalProperties(a), describeAdditionalProperties(b)), location)
+	}
+}
+
+func describeAdditionalProperties(v any) string {
+	if b, ok := v.(bool); ok {
+		return fmt.Sprintf("'%t'", b)
+	}
+	return "schema"
+}
+
+func (d *differ) diffProperties(location []string, a, b map[string]any, direction int) {
+	propsA, propsB := getMap(a, "properties"), getMap(b, "properties")
+	requiredA, requiredB := getStrings(a["required"]), getStrings(b["required"])
+	for _, name := range sortedKeys(propsA) {
+		pLocation := appendLoc(location, "properties", name)
+		propB, ok := propsB[name]
+		if !ok {
+			d.add(lib_models.DocChangeRemoved, direction != directionRequest, "property removed", pLocation)
+			continue
+		}
+		wasRequired, isRequired := slices.Contains(requiredA, name), slices.Contains(requiredB, name)
+		if !wasRequired && isRequired {
+			d.add(lib_models.DocChangeChanged, direction != directionResponse, "property became required", pLocation)
+		}
+		if wasRequired && !isRequired {
+			d.add(lib_models.DocChangeChanged, direction == directionResponse, "property became optional", pLocation)
+		}
+		d.diffSchema(pLocation, asMap(propsA[name]), asMap(propB), direction)
+	}
+	for _, name := range sortedKeys(propsB) {
+		if _, ok := propsA[name]; !ok {
+			d.add(lib_models.DocChangeAdded, direction != directionResponse && slices.Contains(requiredB, name), "property added", appendLoc(location, "properties", name))
+		}
+	}
+}
+
+func (d *differ) diffEnum(location []string, a, b any, direction int) {
+	valsA, valsB := getValues(a), getValues(b)
+	if valsA == nil && valsB == nil {
+		return
+	}
+	var removed, added []string
+	for _, val := range valsA {
+		if !slices.Contains(valsB, val) {
+			removed = append(removed, val)
+		}
+	}
+	for _, val := range valsB {
+		if !slices.Contains(valsA, val) {
+			added = append(added, val)
+		}
+	}
+	if len(removed) > 0 {
+		d.add(lib_models.DocChangeChanged, direction != directionResponse, fmt.Sprintf("enum values removed: %v", removed), appendLoc(location, "enum"))
+	}
+	if len(added) > 0 {
+		d.add(lib_models.DocChangeChanged, direction != directionRequest, fmt.Sprintf("enum values added: %v", added), appendLoc(location, "enum"))
+	}
+}
+
+func getStrings(v any) []string {
+	var strs []string
+	sl, _ := v.([]any)
+	for _, item := range sl {
+		if str, ok := item.(string); ok {
+			strs = append(strs, str)
+		}
+	}
+	return strs
+}
+
+func getValues(v any) []string {
+	var vals []string
+	sl, _ := v.([]any)
+	for _, item := range sl {
+		vals = append(vals, fmt.Sprint(item))
+	}
+	return vals
+}
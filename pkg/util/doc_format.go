@@ -0,0 +1,354 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+	"gopkg.in/yaml.v3"
+	"io"
+	"math"
+	"mime"
+	"strconv"
+	"strings"
+)
+
+const (
+	yamlStrTag   = "!!str"
+	yamlIntTag   = "!!int"
+	yamlFloatTag = "!!float"
+	yamlBoolTag  = "!!bool"
+	yamlNullTag  = "!!null"
+	yamlMergeTag = "!!merge"
+)
+
+const (
+	yamlExpansionFactor = 100
+	yamlMinBudget       = 1 << 20
+)
+
+var ErrYAMLBudgetExceeded = errors.New("yaml document exceeds expansion limit")
+
+var yamlMediaTypes = map[string]struct{}{
+	"application/yaml":   {},
+	"application/x-yaml": {},
+	"text/yaml":          {},
+	"text/x-yaml":        {},
+}
+
+func DetectDocFormat(contentType string, data []byte) string {
+	if contentType != "" {
+		if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+			if _, ok := yamlMediaTypes[mediaType]; ok || strings.HasSuffix(mediaType, "+yaml") {
+				return models.DocFormatYAML
+			}
+			if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+				return models.DocFormatJSON
+			}
+		}
+	}
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), " \t\r\n")
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return models.DocFormatJSON
+	}
+	return models.DocFormatYAML
+}
+
+func YAMLToJSON(data []byte) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	if node.Kind == 0 {
+		return nil, errors.New("empty document")
+	}
+	c := &yamlConverter{
+		buf:    &bytes.Buffer{},
+		budget: max(len(data)*yamlExpansionFactor, yamlMinBudget),
+	}
+	if err := c.writeNode(&node); err != nil {
+		return nil, err
+	}
+	return c.buf.Bytes(), nil
+}
+
+func JSONToYAML(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	node, err := readJSONAsYAMLNode(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("invalid trailing data")
+	}
+	buf := &bytes.Buffer{}
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+	if err = enc.Encode(node); err != nil {
+		return nil, err
+	}
+	if err = enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type yamlConverter struct {
+	buf    *bytes.Buffer
+	budget int
+	visits int
+}
+
+func (c *yamlConverter) visit() error {
+	c.visits++
+	if c.visits > c.budget || c.buf.Len() > c.budget {
+		return ErrYAMLBudgetExceeded
+	}
+	return nil
+}
+
+func (c *yamlConverter) writeNode(node *yaml.Node) error {
+	if err := c.visit(); err != nil {
+		return err
+	}
+	buf := c.buf
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+		return c.writeNode(node.Content[0])
+	case yaml.AliasNode:
+		return c.writeNode(node.Alias)
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := c.writeNode(item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	case yaml.MappingNode:
+		pairs, err := c.getMappingPairs(node)
+		if err != nil {
+			return err
+		}
+		buf.WriteByte('{')
+		for i, pair := range pairs {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, err := json.Marshal(pair[0].Value)
+			if err != nil {
+				return err
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err = c.writeNode(pair[1]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case yaml.ScalarNode:
+		return writeYAMLScalarAsJSON(buf, node)
+	default:
+		return fmt.Errorf("unsupported node kind %d at line %d", node.Kind, node.Line)
+	}
+}
+
+func (c *yamlConverter) getMappingPairs(node *yaml.Node) ([][2]*yaml.Node, error) {
+	if err := c.visit(); err != nil {
+		return nil, err
+	}
+	var pairs [][2]*yaml.Node
+	index := make(map[string]int)
+	set := func(key, val *yaml.Node, override bool) {
+		if i, ok := index[key.Value]; ok {
+			if override {
+				pairs[i][1] = val
+			}
+			return
+		}
+		index[key.Value] = len(pairs)
+		pairs = append(pairs, [2]*yaml.Node{key, val})
+	}
+	var merges []*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, val := resolveYAMLAlias(node.Content[i]), node.Content[i+1]
+		if key.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("unsupported mapping key at line %d", key.Line)
+		}
+		if key.Tag == yamlMergeTag {
+			merges = append(merges, resolveYAMLAlias(val))
+			continue
+		}
+		set(key, val, true)
+	}
+	for _, merge := range merges {
+		var sources []*yaml.Node
+		switch merge.Kind {
+		case yaml.MappingNode:
+			sources = append(sources, merge)
+		case yaml.SequenceNode:
+			for _, item := range merge.Content {
+				sources = append(sources, resolveYAMLAlias(item))
+			}
+		default:
+			return nil, fmt.Errorf("invalid merge value at line %d", merge.Line)
+		}
+		for _, source := range sources {
+			if source.Kind != yaml.MappingNode {
+				return nil, fmt.Errorf("invalid merge value at line %d", source.Line)
+			}
+			sourcePairs, err := c.getMappingPairs(source)
+			if err != nil {
+				return nil, err
+			}
+			for _, pair := range sourcePairs {
+				set(pair[0], pair[1], false)
+			}
+		}
+	}
+	return pairs, nil
+}
+
+func resolveYAMLAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
+
+func writeYAMLScalarAsJSON(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.ShortTag() {
+	case yamlNullTag:
+		buf.WriteString("null")
+		return nil
+	case yamlBoolTag:
+		var b bool
+		if err := node.Decode(&b); err != nil {
+			return err
+		}
+		buf.WriteString(strconv.FormatBool(b))
+		return nil
+	case yamlIntTag:
+		var i int64
+		if err := node.Decode(&i); err == nil {
+			buf.WriteString(strconv.FormatInt(i, 10))
+			return nil
+		}
+		var u uint64
+		if err := node.Decode(&u); err != nil {
+			return err
+		}
+		buf.WriteString(strconv.FormatUint(u, 10))
+		return nil
+	case yamlFloatTag:
+		var f float64
+		if err := node.Decode(&f); err != nil {
+			return err
+		}
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return fmt.Errorf("unsupported float value '%s' at line %d", node.Value, node.Line)
+		}
+		b, err := json.Marshal(f)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+		return nil
+	default:
+		b, err := json.Marshal(node.Value)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+		return nil
+	}
+}
+
+func readJSONAsYAMLNode(dec *json.Decoder) (*yaml.Node, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := token.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			for dec.More() {
+				keyToken, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, ok := keyToken.(string)
+				if !ok {
+					return nil, errors.New("invalid object key")
+				}
+				val, err := readJSONAsYAMLNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: yamlStrTag, Value: key}, val)
+			}
+			if _, err = dec.Token(); err != nil {
+				return nil, err
+			}
+			return node, nil
+		case '[':
+			node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			for dec.More() {
+				val, err := readJSONAsYAMLNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, val)
+			}
+			if _, err = dec.Token(); err != nil {
+				return nil, err
+			}
+			return node, nil
+		default:
+			return nil, fmt.Errorf("unexpected delimiter '%s'", t)
+		}
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: yamlStrTag, Value: t}, nil
+	case json.Number:
+		tag := yamlIntTag
+		if strings.ContainsAny(t.String(), ".eE") {
+			tag = yamlFloatTag
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: t.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: yamlBoolTag, Value: strconv.FormatBool(t)}, nil
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: yamlNullTag, Value: "null"}, nil
+	default:
+		return nil, fmt.Errorf("unexpected token '%v'", t)
+	}
+}
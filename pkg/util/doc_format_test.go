@@ -0,0 +1,161 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+	"testing"
+)
+
+func TestDetectDocFormat(t *testing.T) {
+	tests := []struct {
+		contentType string
+		data        string
+		expected    string
+	}{
+		{"application/json", "a: b", models.DocFormatJSON},
+		{"application/vnd.oai.openapi+json; charset=utf-8", "a: b", models.DocFormatJSON},
+		{"application/yaml", "{}", models.DocFormatYAML},
+		{"application/x-yaml", "{}", models.DocFormatYAML},
+		{"text/yaml; charset=utf-8", "{}", models.DocFormatYAML},
+		{"application/vnd.oai.openapi+yaml", "{}", models.DocFormatYAML},
+		{"text/plain", "\n  {\"a\": \"b\"}", models.DocFormatJSON},
+		{"", "\xef\xbb\xbf{}", models.DocFormatJSON},
+		{"", "[]", models.DocFormatJSON},
+		{"", "openapi: 3.0.0", models.DocFormatYAML},
+		{"application/octet-stream", "asyncapi: 2.6.0", models.DocFormatYAML},
+	}
+	for _, tc := range tests {
+		if f := DetectDocFormat(tc.contentType, []byte(tc.data)); f != tc.expected {
+			t.Errorf("'%s' '%s': expected '%s', got '%s'", tc.contentType, tc.data, tc.expected, f)
+		}
+	}
+}
+
+func TestYAMLToJSON(t *testing.T) {
+	t.Run("document", func(t *testing.T) {
+		data := `openapi: 3.0.0
+info:
+  title: Test
+  version: "1.0"
+paths:
+  /b:
+    get:
+      responses:
+        "200":
+          description: ok
+  /a:
+    get:
+      responses:
+        200:
+          description: ok
+x-values:
+  int: 42
+  big: 18446744073709551615
+  float: 1.5
+  bool: true
+  "null": ~
+  str: "true"
+  date: 2025-01-01
+  list: [1, two]
+`
+		b, err := YAMLToJSON([]byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := `{"openapi":"3.0.0","info":{"title":"Test","version":"1.0"},"paths":{"/b":{"get":{"responses":{"200":{"description":"ok"}}}},"/a":{"get":{"responses":{"200":{"description":"ok"}}}}},"x-values":{"int":42,"big":18446744073709551615,"float":1.5,"bool":true,"null":null,"str":"true","date":"2025-01-01","list":[1,"two"]}}`
+		if string(b) != expected {
+			t.Errorf("expected\n%s\ngot\n%s", expected, string(b))
+		}
+	})
+	t.Run("aliases and merge keys", func(t *testing.T) {
+		data := `base: &base
+  a: 1
+  b: 2
+ext:
+  <<: *base
+  b: 3
+ref: *base
+`
+		b, err := YAMLToJSON([]byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := `{"base":{"a":1,"b":2},"ext":{"b":3,"a":1},"ref":{"a":1,"b":2}}`
+		if string(b) != expected {
+			t.Errorf("expected\n%s\ngot\n%s", expected, string(b))
+		}
+	})
+	t.Run("excessive aliases", func(t *testing.T) {
+		aliases := "a0: &a0 [x, x, x, x, x, x, x, x, x]\n"
+		merges := "m0: &m0 {x: 1}\n"
+		for i := 1; i < 10; i++ {
+			aliases += fmt.Sprintf("a%d: &a%d [*a%d, *a%d, *a%d, *a%d, *a%d, *a%d, *a%d, *a%d, *a%d]\n", i, i, i-1, i-1, i-1, i-1, i-1, i-1, i-1, i-1, i-1)
+			merges += fmt.Sprintf("m%d: &m%d {<<: [*m%d, *m%d, *m%d, *m%d, *m%d, *m%d, *m%d, *m%d, *m%d]}\n", i, i, i-1, i-1, i-1, i-1, i-1, i-1, i-1, i-1, i-1)
+		}
+		for _, data := range []string{aliases, merges} {
+			if _, err := YAMLToJSON([]byte(data)); !errors.Is(err, ErrYAMLBudgetExceeded) {
+				t.Errorf("expected ErrYAMLBudgetExceeded, got %v", err)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for _, data := range []string{"", "a: [", "a: .inf", "? [a]\n: b"} {
+			if _, err := YAMLToJSON([]byte(data)); err == nil {
+				t.Errorf("'%s': expected error", data)
+			}
+		}
+	})
+}
+
+func TestJSONToYAML(t *testing.T) {
+	data := `{"openapi":"3.0.0","paths":{"/b":{},"/a":{}},"x-values":{"int":42,"float":1.5,"bool":false,"null":null,"str":"true","num":"1","list":[1,"a"]}}`
+	b, err := JSONToYAML([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `openapi: 3.0.0
+paths:
+  /b: {}
+  /a: {}
+x-values:
+  int: 42
+  float: 1.5
+  bool: false
+  "null": null
+  str: "true"
+  num: "1"
+  list:
+    - 1
+    - a
+`
+	if string(b) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, string(b))
+	}
+	b, err = YAMLToJSON(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != data {
+		t.Errorf("expected\n%s\ngot\n%s", data, string(b))
+	}
+	if _, err = JSONToYAML([]byte(`{"a":1} {}`)); err == nil {
+		t.Error("expected error")
+	}
+}
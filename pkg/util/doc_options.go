@@ -0,0 +1,100 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"errors"
+	"github.com/SENERGY-Platform/api-docs-provider/pkg/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DocOptionPath     = "path"
+	DocOptionDisabled = "disabled"
+	DocOptionProtocol = "protocol"
+	DocOptionTimeout  = "timeout"
+)
+
+var docProtocols = map[string]struct{}{
+	"http":  {},
+	"https": {},
+}
+
+func ParseDocOption(opts *models.DocOptions, option string) error {
+	key, val, hasVal := strings.Cut(option, "=")
+	if key == DocOptionDisabled {
+		if hasVal {
+			return errors.New("unexpected value")
+		}
+		opts.Disabled = true
+		return nil
+	}
+	return SetDocOption(opts, key, val)
+}
+
+func SetDocOption(opts *models.DocOptions, key, val string) error {
+	switch key {
+	case DocOptionDisabled:
+		if val == "" {
+			opts.Disabled = true
+			return nil
+		}
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return err
+		}
+		opts.Disabled = b
+	case DocOptionPath:
+		if !strings.HasPrefix(val, "/") {
+			return errors.New("invalid path")
+		}
+		opts.Path = val
+	case DocOptionProtocol:
+		if _, ok := docProtocols[val]; !ok {
+			return errors.New("invalid protocol")
+		}
+		opts.Protocol = val
+	case DocOptionTimeout:
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return err
+		}
+		if d <= 0 {
+			return errors.New("invalid timeout")
+		}
+		opts.Timeout = d
+	default:
+		return errors.New("unknown option")
+	}
+	return nil
+}
+
+func MergeDocOptions(a, b models.DocOptions) models.DocOptions {
+	if a.Path == "" {
+		a.Path = b.Path
+	}
+	if a.Protocol == "" {
+		a.Protocol = b.Protocol
+	}
+	if a.Timeout == 0 {
+		a.Timeout = b.Timeout
+	}
+	a.Disabled = a.Disabled || b.Disabled
+	return a
+}
@@ -29,4 +29,16 @@ const (
 	VersionKey       = "version"
 	ConfigValuesKey  = "config_values"
 	ComponentKey     = "component"
+	DocPathKey       = "doc_path"
+	DocOptionsKey    = "doc_options"
+	RouteKey         = "route"
+	PathKey          = "path"
+	NameKey          = "name"
+	NamespaceKey     = "namespace"
+	AttemptKey       = "attempt"
+	DelayKey         = "delay"
+	OutcomesKey      = "outcomes"
+	TotalKey         = "total"
+	RevisionKey      = "revision"
+	ChangesKey       = "changes"
 )